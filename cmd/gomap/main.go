@@ -0,0 +1,99 @@
+// Command gomap runs gomap from the command line.
+//
+//	gomap serve [flags]
+//
+// serves the REST API of gomap.Server. The token and the SNMP communities
+// requests may use are read from the GOMAP_TOKEN and
+// GOMAP_SNMP_COMMUNITIES environment variables so they do not show up in
+// the process list, and a token or allowed ranges are required.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"github.com/JustinTimperio/gomap"
+)
+
+func main() {
+	if len(os.Args) < 2 || os.Args[1] != "serve" {
+		fmt.Fprintln(os.Stderr, "usage: gomap serve [flags]")
+		os.Exit(2)
+	}
+	if err := serve(os.Args[2:]); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// serve runs the REST API until it is interrupted
+func serve(args []string) error {
+	fs := flag.NewFlagSet("serve", flag.ExitOnError)
+	addr := fs.String("addr", "127.0.0.1:8080", "address to listen on")
+	workers := fs.Int("workers", 2, "scans run at once")
+	queue := fs.Int("queue", 100, "scans that may wait for a worker")
+	auditLog := fs.String("audit-log", "", "file scans are recorded in")
+	allow := fs.String("allow", "", "comma separated ranges, addresses and domains that may be scanned")
+	deny := fs.String("deny", "", "comma separated ranges, addresses and domains that may not be scanned")
+	allowPublic := fs.Bool("allow-public", false, "allow public addresses outside the allowed ranges")
+	maxHosts := fs.Int("max-hosts", 4096, "most hosts a scan of the local range may cover, -1 for no cap")
+	checks := fs.String("checks", "", `comma separated checks requests may run, "all" for every check`)
+	iface := fs.String("interface", "", "interface scans are sent from")
+	sourceIP := fs.String("source-ip", "", "address scans are sent from")
+	dns := fs.String("dns", "", "comma separated DNS servers used instead of the system resolver")
+	fs.Parse(args)
+
+	scope := &gomap.Scope{
+		Allow:       splitList(*allow),
+		Deny:        splitList(*deny),
+		AllowPublic: *allowPublic,
+	}
+	server, err := gomap.NewServer(gomap.ServerOptions{
+		Workers:   *workers,
+		QueueSize: *queue,
+		Token:     os.Getenv("GOMAP_TOKEN"),
+		Scope:     scope,
+		AuditLog:  *auditLog,
+
+		MaxHosts:        *maxHosts,
+		Checks:          splitList(*checks),
+		SNMPCommunities: splitList(os.Getenv("GOMAP_SNMP_COMMUNITIES")),
+		Interface:       *iface,
+		SourceIP:        *sourceIP,
+		DNSServers:      splitList(*dns),
+	})
+	if err != nil {
+		return err
+	}
+	defer server.Close()
+
+	srv := &http.Server{Addr: *addr, Handler: server}
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-stop
+		srv.Close()
+	}()
+
+	log.Printf("gomap: serving on %s", *addr)
+	if err := srv.ListenAndServe(); err != http.ErrServerClosed {
+		return err
+	}
+	return nil
+}
+
+// splitList splits a comma separated flag, dropping empty entries
+func splitList(s string) []string {
+	var list []string
+	for _, e := range strings.Split(s, ",") {
+		if e = strings.TrimSpace(e); e != "" {
+			list = append(list, e)
+		}
+	}
+	return list
+}
@@ -2,16 +2,40 @@ package gomap
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net"
+	"strings"
+	"time"
 )
 
 // IPScanResult contains the results of a scan on a single ip
 type IPScanResult struct {
 	Hostname string
-	IP       []net.IP
-	Results  []portResult
+	// Target is the address or name the host was scanned as
+	Target string `json:",omitempty"`
+	// Names holds every name found by the reverse lookup of the host
+	Names []HostName
+	// Domains holds the enumerated subdomains that resolve to the host
+	Domains []string
+	IP      []net.IP
+	// Address is the address in IP that was scanned
+	Address net.IP `json:",omitempty"`
+	Results []portResult
+	// Addresses holds the results of each address when a hostname
+	// with several addresses is scanned with AllAddresses
+	Addresses []AddressResult
+	NetBIOS   *NetBIOSInfo
+	SMB       *SMBInfo
+	SNMP      *SNMPInfo
+	Findings  []Finding
+	MDNS      []MDNSService
+	UPnP      []UPnPDevice
+	Route     *Route
+	Trace     []TraceHop
+	OS        []OSMatch
 }
 
 // JsonRange contains a slice of of JsonIP results
@@ -25,149 +49,611 @@ type JsonIP struct {
 	Hostname string
 	Active   bool
 	Ports    []string
+	Names    []HostName              `json:",omitempty"`
+	Domains  []string                `json:",omitempty"`
+	Versions map[int]*ServiceVersion `json:",omitempty"`
+	Vulns    map[int][]Vulnerability `json:",omitempty"`
+	SSH      map[int]*SSHInfo        `json:",omitempty"`
+	NetBIOS  *NetBIOSInfo            `json:",omitempty"`
+	SMB      *SMBInfo                `json:",omitempty"`
+	SNMP     *SNMPInfo               `json:",omitempty"`
+	Findings []Finding               `json:",omitempty"`
+	MDNS     []MDNSService           `json:",omitempty"`
+	UPnP     []UPnPDevice            `json:",omitempty"`
+	Trace    []TraceHop              `json:",omitempty"`
+	OS       []OSMatch               `json:",omitempty"`
+
+	Addresses   []JsonAddress `json:",omitempty"`
+	Differences []string      `json:",omitempty"`
+}
+
+// JsonAddress contains the open ports of one address of a hostname
+// and what the host probes found on it
+type JsonAddress struct {
+	IP       string
+	Ports    []string
+	NetBIOS  *NetBIOSInfo `json:",omitempty"`
+	SMB      *SMBInfo     `json:",omitempty"`
+	SNMP     *SNMPInfo    `json:",omitempty"`
+	Findings []Finding    `json:",omitempty"`
+	Trace    []TraceHop   `json:",omitempty"`
+	OS       []OSMatch    `json:",omitempty"`
 }
 
 type portResult struct {
 	Port    int
 	State   bool
+	Status  PortState
 	Service string
+	Version *ServiceVersion `json:",omitempty"`
+	Vulns   []Vulnerability `json:",omitempty"`
+	SSH     *SSHInfo        `json:",omitempty"`
 }
 
-type tcpHeader struct {
-	SrcPort       uint16
-	DstPort       uint16
-	SeqNum        uint32
-	AckNum        uint32
-	Flags         uint16
-	Window        uint16
-	ChkSum        uint16
-	UrgentPointer uint16
-}
+// PortState describes what a scan was able to determine about a port
+type PortState string
 
-type tcpOption struct {
-	Kind   uint8
-	Length uint8
-	Data   []byte
-}
+const (
+	PortOpen           PortState = "open"
+	PortClosed         PortState = "closed"
+	PortFiltered       PortState = "filtered"
+	PortUnfiltered     PortState = "unfiltered"
+	PortOpenOrFiltered PortState = "open|filtered"
+)
+
+// ScanType selects the technique used to probe each port
+type ScanType int
+
+const (
+	// ScanConnect completes a full connection with the OS network stack
+	ScanConnect ScanType = iota
+	// ScanSYN sends a SYN and waits for a SYN-ACK (Stealth)
+	ScanSYN
+	// ScanFIN, ScanNULL and ScanXmas send packets without a SYN which
+	// closed ports answer with a RST and open ports ignore
+	ScanFIN
+	ScanNULL
+	ScanXmas
+	// ScanACK sends an ACK to map firewall rulesets. Ports which
+	// answer with a RST are unfiltered.
+	ScanACK
+	// ScanWindow is an ACK scan that uses the window size of
+	// the RST to tell open and closed ports apart on some systems
+	ScanWindow
+)
+
+// ErrRangeTooLarge is returned when a range scan would exceed ScanOptions.MaxHosts
+var ErrRangeTooLarge = errors.New("range too large")
 
 // RangeScanResult contains multiple IPScanResults
 type RangeScanResult []*IPScanResult
 
+// ScanOptions contains the settings used by a scan
+type ScanOptions struct {
+	Proto    string
+	Fastscan bool
+	Stealth  bool
+
+	// AuditLog is the path of a file every scan appends a JSON line to
+	// when it starts and ends, recording Operator, the options, the
+	// targets and a summary of the results. Operator defaults to the user
+	// running the process. ClaimedOperator is an operator named by a client
+	// that could not be verified, recorded next to Operator. A scan whose
+	// start cannot be recorded does not run.
+	AuditLog        string
+	Operator        string
+	ClaimedOperator string
+
+	// Context cancels the scan once done, leaving any checkpoint ready to
+	// be resumed. Progress is called as ports and hosts are finished
+	// instead of printing the port count. Neither is stored in checkpoints.
+	Context  context.Context    `json:"-"`
+	Progress func(ScanProgress) `json:"-"`
+
+	// Scope limits the addresses and names the scan may touch. Targets
+	// are checked before scanning and names again once resolved, targets
+	// outside it are refused with a *ScopeError. A nil Scope refuses public
+	// addresses, set Scope.AllowPublic to scan them.
+	Scope *Scope
+
+	// Interface and SourceIP choose the local address scans are sent
+	// from. When neither is set the address is picked for each target
+	// from the routing table.
+	Interface string
+	SourceIP  string
+
+	// ScanType selects the raw packet scan technique.
+	// Stealth without a ScanType is the same as ScanSYN.
+	ScanType ScanType
+
+	// Checkpoint is the path of a file the scan state is periodically
+	// written to so an interrupted scan can be continued with ResumeScan
+	Checkpoint         string
+	CheckpointInterval time.Duration
+
+	// Services is the service database used to pick and name ports.
+	// ServiceFiles are nmap-services formatted files loaded on top of a copy
+	// of it to override entries. Services is not stored in checkpoints, resumed
+	// scans use the default database with ServiceFiles applied.
+	Services     *ServiceDB `json:"-"`
+	ServiceFiles []string
+
+	// TopPorts is the number of most frequently open ports to scan.
+	// Defaults to 100 in fastscan mode and every known port otherwise.
+	TopPorts int
+
+	// DNSServers are the servers used to resolve targets and look up
+	// their names instead of the system resolver. NoDNS skips the reverse
+	// lookups of scanned addresses. Resolver can be set to share a
+	// cache between scans, it is not stored in checkpoints.
+	DNSServers []string
+	NoDNS      bool
+	Resolver   *Resolver `json:"-"`
+
+	// AllAddresses scans every address a hostname resolves to
+	// separately instead of only the one picked by the resolver
+	AllAddresses bool
+
+	// AllSubnets makes ScanRange scan every ipv4 range attached to the
+	// chosen interface, or to every non-loopback interface, instead of
+	// only the range of the source address
+	AllSubnets bool
+
+	// MaxHosts is a safety limit on the number of hosts ScanRange will scan.
+	// Defaults to 4096, a negative value removes the limit.
+	MaxHosts int
+
+	// Traceroute records the path to each host after its ports are
+	// scanned using TracerouteProto ("tcp", "udp" or "icmp") probes
+	Traceroute      bool
+	TracerouteProto string
+	MaxHops         int
+
+	// VersionDetection identifies the service and version on open tcp
+	// ports by sending the probes in Probes, which defaults to the embedded
+	// database, with ProbeFiles loaded on top of a copy of it. Only probes with a rarity up
+	// to VersionIntensity (1-9, default 7) are sent to ports they do not
+	// list. Probes is not stored in checkpoints.
+	VersionDetection bool
+	VersionIntensity int
+	Probes           *ProbeDB `json:"-"`
+	ProbeFiles       []string
+
+	// VulnFeeds are NVD JSON feeds the detected versions are matched
+	// against to list the known vulnerabilities of each port. Vulns can be
+	// set instead to share a loaded database, it is not stored in
+	// checkpoints. Either enables VersionDetection.
+	VulnFeeds []string
+	Vulns     *VulnDB `json:"-"`
+
+	// SSHInspection records the banner, algorithms and host keys of
+	// open ssh ports
+	SSHInspection bool
+
+	// MDNS makes ScanRange discover the services announced over multicast
+	// DNS first. Their host names are used for hosts without PTR records.
+	MDNS bool
+
+	// SSDP makes ScanRange search for UPnP devices first and read their
+	// device descriptions
+	SSDP bool
+
+	// SMBInspection queries the NetBIOS names of hosts with Windows file
+	// sharing ports open and the SMB dialects offered on port 445
+	SMBInspection bool
+
+	// SNMPCommunities are read-only communities tried against udp port
+	// 161 of each scanned host to read its system and interface tables.
+	// They are secrets, redacted in the audit log and left out of checkpoints.
+	SNMPCommunities []string
+
+	// Checks are the names of registered checks to run against each host
+	// and its open ports, "all" runs every registered check. CheckWorkers
+	// checks run at once per host, each limited to CheckTimeout. gomap
+	// registers non-destructive misconfiguration checks such as ftp-anon,
+	// redis-noauth and http-dirlist, see RegisteredChecks.
+	Checks       []string
+	CheckWorkers int
+	CheckTimeout time.Duration
+
+	// OSDetection guesses the operating system of each host by sending
+	// crafted TCP, ICMP and UDP probes once its ports are scanned
+	OSDetection bool
+}
+
+// ScanProgress reports how far a scan has got. Port counts are reported
+// for the host being scanned and host counts once each host is finished.
+type ScanProgress struct {
+	Host       string
+	PortsDone  int `json:",omitempty"`
+	PortsTotal int `json:",omitempty"`
+	HostsDone  int `json:",omitempty"`
+	HostsTotal int `json:",omitempty"`
+}
+
 // ScanIP scans a single IP for open ports
 func ScanIP(hostname string, proto string, fastscan bool, stealth bool) (*IPScanResult, error) {
-	laddr, err := getLocalIP()
+	return ScanIPWithOptions(hostname, ScanOptions{Proto: proto, Fastscan: fastscan, Stealth: stealth})
+}
+
+// ScanRange scans every address on a CIDR for open ports
+func ScanRange(proto string, fastscan bool, stealth bool) (RangeScanResult, error) {
+	return ScanRangeWithOptions(ScanOptions{Proto: proto, Fastscan: fastscan, Stealth: stealth})
+}
+
+// ScanIPWithOptions scans a single IP for open ports using the provided options
+func ScanIPWithOptions(hostname string, opts ScanOptions) (*IPScanResult, error) {
+	laddr, err := prepareScan(&opts)
 	if err != nil {
 		return nil, err
 	}
 
-	if stealth {
-		if canSocketBind(laddr) == false {
-			return nil, fmt.Errorf("socket: operation not permitted")
-		}
+	a, err := startAudit(&opts, "start", []string{hostname})
+	if err != nil {
+		return nil, err
 	}
-	return scanIPPorts(hostname, laddr, proto, fastscan, stealth)
+	result, err := scanIP(hostname, laddr, &opts)
+	return result, a.finish(RangeScanResult{result}, err)
 }
 
-// ScanRange scans every address on a CIDR for open ports
-func ScanRange(proto string, fastscan bool, stealth bool) (RangeScanResult, error) {
-	laddr, err := getLocalIP()
+// ScanRangeWithOptions scans every address on a CIDR for open ports using the provided options
+func ScanRangeWithOptions(opts ScanOptions) (RangeScanResult, error) {
+	laddr, err := prepareScan(&opts)
 	if err != nil {
 		return nil, err
 	}
+	return scanIPRange(laddr, &opts)
+}
 
-	if stealth {
-		if canSocketBind(laddr) == false {
-			return nil, fmt.Errorf("socket: operation not permitted")
+// ResumeScan continues a scan from the checkpoint file at path. Hosts and
+// ports already scanned are skipped and their results merged with the rest.
+// Checkpoints do not store SNMP communities, a scan that queried SNMP must
+// be given them again.
+func ResumeScan(path string, communities ...string) (RangeScanResult, error) {
+	cp, err := loadCheckpoint(path)
+	if err != nil {
+		return nil, err
+	}
+	if len(cp.Options.SNMPCommunities) > 0 {
+		if len(communities) == 0 && !cp.Complete {
+			return nil, ErrCommunitiesRequired
 		}
+		cp.Options.SNMPCommunities = communities
+	}
+	if cp.Complete {
+		addDomains(cp.Results, cp.Domains)
+		addMDNS(cp.Results, cp.MDNS)
+		addUPnP(cp.Results, cp.UPnP)
+		return cp.Results, nil
 	}
-	return scanIPRange(laddr, proto, fastscan, stealth)
+
+	laddr, err := prepareScan(&cp.Options)
+	if err != nil {
+		return nil, err
+	}
+	a, err := startAudit(&cp.Options, "resume", cp.remaining())
+	if err != nil {
+		return nil, err
+	}
+	cp.Options.Resolver.addHostNames(cp.Domains)
+	cp.Options.Resolver.addHostNames(mdnsNames(cp.MDNS))
+	results, err := scanHosts(cp.remaining(), laddr, &cp.Options, cp)
+	addDomains(results, cp.Domains)
+	addMDNS(results, cp.MDNS)
+	addUPnP(results, cp.UPnP)
+	return results, a.finish(results, err)
 }
 
-// String with the results of a single scanned IP
-func (results *IPScanResult) String() string {
-	b := bytes.NewBuffer(nil)
-	ip := results.IP[len(results.IP)-1]
+// context returns the context of the scan
+func (opts *ScanOptions) context() context.Context {
+	if opts.Context == nil {
+		return context.Background()
+	}
+	return opts.Context
+}
 
-	fmt.Fprintf(b, "\nHost: %s (%s)\n", results.Hostname, ip)
+// progress reports p to opts.Progress, or prints the port count if it is unset
+func (opts *ScanOptions) progress(p ScanProgress) {
+	if opts.Progress != nil {
+		opts.Progress(p)
+	} else if p.PortsTotal > 0 {
+		fmt.Printf("\033[2K\rHost: %s | Ports Scanned %d/%d", p.Host, p.PortsDone, p.PortsTotal)
+	}
+}
 
-	active := false
-	for _, r := range results.Results {
-		if r.State {
-			active = true
-			break
+// rawSockets reports if the scan sends packets through raw sockets
+func (opts *ScanOptions) rawSockets() bool {
+	return opts.Stealth || opts.Traceroute || opts.OSDetection
+}
+
+// redacted returns a copy of the options with secrets such as SNMP
+// communities replaced, for logs and status pages
+func (opts *ScanOptions) redacted() *ScanOptions {
+	c := *opts
+	if len(c.SNMPCommunities) > 0 {
+		c.SNMPCommunities = make([]string, len(opts.SNMPCommunities))
+		for i := range c.SNMPCommunities {
+			c.SNMPCommunities[i] = "REDACTED"
 		}
 	}
-	if active {
-		fmt.Fprintf(b, "\t|     %s	%s\n", "Port", "Service")
-		fmt.Fprintf(b, "\t|     %s	%s\n", "----", "-------")
-		for _, v := range results.Results {
-			if v.State {
-				fmt.Fprintf(b, "\t|---- %d	%s\n", v.Port, v.Service)
+	return &c
+}
+
+// loadFiles merges ServiceFiles and ProbeFiles into copies of Services and
+// Probes, the default databases and those passed in may be shared between scans
+func (opts *ScanOptions) loadFiles() error {
+	if len(opts.ServiceFiles) > 0 {
+		services := NewServiceDB()
+		services.Merge(opts.Services)
+		for _, f := range opts.ServiceFiles {
+			db, err := LoadServices(f)
+			if err != nil {
+				return err
 			}
+			services.Merge(db)
 		}
-	} else if results.Hostname != "Unknown" {
-		fmt.Fprintf(b, "\t|---- %s\n", "No Open Ports Found")
+		opts.Services = services
 	}
-	return b.String()
+
+	if len(opts.ProbeFiles) > 0 && opts.Probes != nil {
+		probes := NewProbeDB()
+		probes.Merge(opts.Probes)
+		for _, f := range opts.ProbeFiles {
+			db, err := LoadProbes(f)
+			if err != nil {
+				return err
+			}
+			probes.Merge(db)
+		}
+		opts.Probes = probes
+	}
+	return nil
 }
 
-// String with the results of multiple scanned IP's
-func (results RangeScanResult) String() string {
-	b := bytes.NewBuffer(nil)
-	for _, r := range results {
-		ip := r.IP[len(r.IP)-1]
+// prepareScan fills in option defaults and returns the local address to scan from
+func prepareScan(opts *ScanOptions) (string, error) {
+	if opts.Proto == "" {
+		opts.Proto = "tcp"
+	}
+	if opts.CheckpointInterval <= 0 {
+		opts.CheckpointInterval = 30 * time.Second
+	}
+	if opts.Stealth && opts.ScanType == ScanConnect {
+		opts.ScanType = ScanSYN
+	}
+	if opts.ScanType != ScanConnect {
+		opts.Stealth = true
+		if opts.ScanType > ScanWindow {
+			return "", fmt.Errorf("unknown scan type: %s", opts.ScanType)
+		}
+		if serviceProto(opts.Proto) != "tcp" {
+			return "", fmt.Errorf("%s scan requires the tcp protocol", opts.ScanType)
+		}
+	}
+	if err := opts.Scope.validate(); err != nil {
+		return "", err
+	}
+	if opts.MaxHosts == 0 {
+		opts.MaxHosts = 4096
+	}
+	if opts.TopPorts == 0 && opts.Fastscan {
+		opts.TopPorts = 100
+	}
 
-		fmt.Fprintf(b, "\nHost: %s (%s)\n", r.Hostname, ip)
-		active := false
+	if opts.Services == nil {
+		opts.Services = DefaultServices()
+	}
 
-		for _, r := range r.Results {
-			if r.State {
-				active = true
-				break
-			}
+	if len(opts.VulnFeeds) > 0 && opts.Vulns == nil {
+		db, err := LoadVulnDB(opts.VulnFeeds...)
+		if err != nil {
+			return "", err
 		}
-		if active {
-			fmt.Fprintf(b, "\t|     %s	%s\n", "Port", "Service")
-			fmt.Fprintf(b, "\t|     %s	%s\n", "----", "-------")
-			for _, v := range r.Results {
-				if v.State {
-					fmt.Fprintf(b, "\t|---- %d	%s\n", v.Port, v.Service)
-				}
-			}
-		} else if r.Hostname != "Unknown" {
-			fmt.Fprintf(b, "\t|---- %s\n", "No Open Ports Found")
+		opts.Vulns = db
+	}
+	if opts.Vulns != nil {
+		opts.VersionDetection = true
+	}
+
+	if opts.VersionDetection {
+		if opts.VersionIntensity == 0 {
+			opts.VersionIntensity = 7
+		}
+		if opts.VersionIntensity < 1 || opts.VersionIntensity > 9 {
+			return "", fmt.Errorf("version intensity must be between 1 and 9: %d", opts.VersionIntensity)
 		}
+		if opts.Probes == nil {
+			opts.Probes = DefaultProbes()
+		}
+	}
+	if err := opts.loadFiles(); err != nil {
+		return "", err
 	}
 
+	if opts.Resolver == nil {
+		opts.Resolver = NewResolver(opts.DNSServers...)
+	}
+
+	laddr, err := localAddress(opts)
+	if err != nil {
+		return "", err
+	}
+
+	if len(opts.Checks) > 0 {
+		if _, err := enabledChecks(opts.Checks); err != nil {
+			return "", err
+		}
+		if opts.CheckWorkers <= 0 {
+			opts.CheckWorkers = 10
+		}
+		if opts.CheckTimeout <= 0 {
+			opts.CheckTimeout = 10 * time.Second
+		}
+	}
+
+	if opts.Traceroute {
+		if opts.TracerouteProto == "" {
+			opts.TracerouteProto = "tcp"
+		}
+		if opts.MaxHops <= 0 {
+			opts.MaxHops = 30
+		}
+	}
+
+	// Without a chosen address only the permission is checked here, the
+	// address routed to each target is checked as the target is scanned
+	if opts.rawSockets() {
+		bind := laddr
+		if opts.SourceIP == "" && opts.Interface == "" {
+			bind = "0.0.0.0"
+		}
+		if canSocketBind(bind) == false {
+			return "", fmt.Errorf("socket: operation not permitted")
+		}
+	}
+	return laddr, nil
+}
+
+// String with the results of a single scanned IP
+func (results *IPScanResult) String() string {
+	b := bytes.NewBuffer(nil)
+
+	if len(results.Addresses) > 0 {
+		results.writeAddresses(b)
+	} else {
+		fmt.Fprintf(b, "\nHost: %s (%s)\n", results.Hostname, results.address())
+		writePorts(b, results.Results, results.Hostname != "Unknown")
+	}
+
+	// A single confirmed name is already shown in the header
+	if len(results.Names) > 1 || (len(results.Names) == 1 && !results.Names[0].Confirmed) {
+		var names []string
+		for _, n := range results.Names {
+			names = append(names, n.String())
+		}
+		fmt.Fprintf(b, "\t|---- Names: %s\n", strings.Join(names, ", "))
+	}
+	if len(results.Domains) > 0 {
+		fmt.Fprintf(b, "\t|---- Domains: %s\n", strings.Join(results.Domains, ", "))
+	}
+
+	results.writeHost(b)
 	return b.String()
 }
 
-// Contains a marshaled struct containing the results for a ip scan
-func (results *IPScanResult) Json() (string, error) {
-	var ipdata JsonIP
-	fmt.Println(results.IP)
-	ipdata.IP = fmt.Sprintf("%s", results.IP[len(results.IP)-1])
-	ipdata.Hostname = results.Hostname
+// writeHost writes what the host probes, discovery and checks found
+func (results *IPScanResult) writeHost(b *bytes.Buffer) {
+	if results.NetBIOS != nil {
+		fmt.Fprintf(b, "\t|---- NetBIOS: %s\n", results.NetBIOS)
+	}
+	if results.SMB != nil {
+		fmt.Fprintf(b, "\t|---- SMB: %s\n", results.SMB)
+	}
+	if results.SNMP != nil {
+		fmt.Fprintf(b, "\t|---- SNMP: %s\n", results.SNMP)
+	}
+	for _, m := range results.MDNS {
+		fmt.Fprintf(b, "\t|---- mDNS: %s\n", m)
+	}
+	for _, d := range results.UPnP {
+		fmt.Fprintf(b, "\t|---- UPnP: %s\n", d)
+	}
+
+	for _, m := range results.OS {
+		fmt.Fprintf(b, "\t|---- OS: %s\n", m)
+	}
 
+	for _, f := range results.Findings {
+		fmt.Fprintf(b, "\t|---- %s\n", f)
+	}
+
+	if len(results.Trace) > 0 {
+		fmt.Fprintf(b, "\t|     %s	%s	%s\n", "Hop", "RTT", "Address")
+		for _, h := range results.Trace {
+			fmt.Fprintf(b, "\t|---- %s\n", h)
+		}
+	}
+}
+
+// writePorts writes the table of found ports
+func writePorts(b *bytes.Buffer, results []portResult, showEmpty bool) {
 	active := false
-	for _, r := range results.Results {
+	for _, r := range results {
 		if r.State {
 			active = true
 			break
 		}
 	}
-	ipdata.Active = active
 
 	if active {
-		for _, v := range results.Results {
+		fmt.Fprintf(b, "\t|     %s	%s\n", "Port", "Service")
+		fmt.Fprintf(b, "\t|     %s	%s\n", "----", "-------")
+		for _, v := range results {
 			if v.State {
-				entry := fmt.Sprintf("%d: %s", v.Port, v.Service)
-				ipdata.Ports = append(ipdata.Ports, entry)
+				fmt.Fprintf(b, "\t|---- %d	%s\n", v.Port, v.label())
+				writeDetails(b, v)
 			}
 		}
+	} else if showEmpty {
+		fmt.Fprintf(b, "\t|---- %s\n", "No Open Ports Found")
+	}
+}
+
+// writeDetails writes what the inspection probes found on a port
+func writeDetails(b *bytes.Buffer, r portResult) {
+	if r.Version != nil {
+		fmt.Fprintf(b, "\t|         Version: %s\n", r.Version)
+	}
+	for _, v := range r.Vulns {
+		fmt.Fprintf(b, "\t|         Vulnerable: %s\n", v)
+	}
+	if r.SSH != nil {
+		fmt.Fprintf(b, "\t|         %s\n", r.SSH.Banner)
+		for _, k := range r.SSH.HostKeys {
+			fmt.Fprintf(b, "\t|         Host key: %s\n", k)
+		}
+		if len(r.SSH.Deprecated) > 0 {
+			fmt.Fprintf(b, "\t|         Deprecated: %s\n", strings.Join(r.SSH.Deprecated, ", "))
+		}
+	}
+}
+
+// writeAddresses writes the ports of each address of a hostname followed
+// by the ports that are not the same on every address
+func (results *IPScanResult) writeAddresses(b *bytes.Buffer) {
+	var ips []string
+	for _, a := range results.Addresses {
+		ips = append(ips, a.IP.String())
+	}
+	fmt.Fprintf(b, "\nHost: %s (%s)\n", results.Hostname, strings.Join(ips, ", "))
+
+	for _, a := range results.Addresses {
+		fmt.Fprintf(b, "\t|---- Address: %s\n", a.IP)
+		writePorts(b, a.Results, true)
+		a.host(results.Hostname).writeHost(b)
+	}
+
+	if diffs := results.Differences(); len(diffs) > 0 {
+		fmt.Fprintf(b, "\t|     %s\n", "Differences")
+		for _, d := range diffs {
+			fmt.Fprintf(b, "\t|---- %s\n", d)
+		}
 	}
+}
 
-	j, err := json.MarshalIndent(ipdata, "", "	")
+// String with the results of multiple scanned IP's
+func (results RangeScanResult) String() string {
+	b := bytes.NewBuffer(nil)
+	for _, r := range results {
+		b.WriteString(r.String())
+	}
+
+	return b.String()
+}
+
+// Contains a marshaled struct containing the results for a ip scan
+func (results *IPScanResult) Json() (string, error) {
+	j, err := json.MarshalIndent(results.jsonIP(), "", "	")
 	if err != nil {
 		return "", err
 	}
@@ -179,28 +665,7 @@ func (results RangeScanResult) Json() (string, error) {
 	var data JsonRange
 
 	for _, r := range results {
-		var ipdata JsonIP
-		ipdata.IP = fmt.Sprintf("%s", r.IP[len(r.IP)-1])
-		ipdata.Hostname = r.Hostname
-
-		active := false
-		for _, r := range r.Results {
-			if r.State {
-				active = true
-				break
-			}
-		}
-		ipdata.Active = active
-
-		if active {
-			for _, v := range r.Results {
-				if v.State {
-					entry := fmt.Sprintf("%d: %s", v.Port, v.Service)
-					ipdata.Ports = append(ipdata.Ports, entry)
-				}
-			}
-		}
-		data.results = append(data.results, ipdata)
+		data.results = append(data.results, r.jsonIP())
 	}
 
 	j, err := json.MarshalIndent(data.results, "", "	")
@@ -209,3 +674,97 @@ func (results RangeScanResult) Json() (string, error) {
 	}
 	return string(j), nil
 }
+
+// jsonIP converts the results of a single scanned IP to a JSON entry
+func (results *IPScanResult) jsonIP() JsonIP {
+	var ipdata JsonIP
+	ipdata.IP = fmt.Sprintf("%s", results.address())
+	ipdata.Hostname = results.Hostname
+	ipdata.Active = results.active()
+
+	if ipdata.Active {
+		for _, v := range results.Results {
+			if v.State {
+				entry := fmt.Sprintf("%d: %s", v.Port, v.label())
+				ipdata.Ports = append(ipdata.Ports, entry)
+				if v.Version != nil {
+					if ipdata.Versions == nil {
+						ipdata.Versions = make(map[int]*ServiceVersion)
+					}
+					ipdata.Versions[v.Port] = v.Version
+				}
+				if len(v.Vulns) > 0 {
+					if ipdata.Vulns == nil {
+						ipdata.Vulns = make(map[int][]Vulnerability)
+					}
+					ipdata.Vulns[v.Port] = v.Vulns
+				}
+				if v.SSH != nil {
+					if ipdata.SSH == nil {
+						ipdata.SSH = make(map[int]*SSHInfo)
+					}
+					ipdata.SSH[v.Port] = v.SSH
+				}
+			}
+		}
+	}
+	for _, a := range results.Addresses {
+		addr := JsonAddress{
+			IP:       a.IP.String(),
+			NetBIOS:  a.NetBIOS,
+			SMB:      a.SMB,
+			SNMP:     a.SNMP,
+			Findings: a.Findings,
+			Trace:    a.Trace,
+			OS:       a.OS,
+		}
+		for _, v := range a.Results {
+			if v.State {
+				addr.Ports = append(addr.Ports, fmt.Sprintf("%d: %s", v.Port, v.label()))
+			}
+		}
+		ipdata.Addresses = append(ipdata.Addresses, addr)
+	}
+	for _, d := range results.Differences() {
+		ipdata.Differences = append(ipdata.Differences, d.String())
+	}
+	ipdata.Names = results.Names
+	ipdata.Domains = results.Domains
+	ipdata.NetBIOS = results.NetBIOS
+	ipdata.SMB = results.SMB
+	ipdata.SNMP = results.SNMP
+	ipdata.Findings = results.Findings
+	ipdata.MDNS = results.MDNS
+	ipdata.UPnP = results.UPnP
+
+	ipdata.Trace = results.Trace
+	ipdata.OS = results.OS
+	return ipdata
+}
+
+// address returns the address that was scanned. Results stored before
+// it was recorded fall back to the first address of the host.
+func (results *IPScanResult) address() net.IP {
+	if results.Address != nil || len(results.IP) == 0 {
+		return results.Address
+	}
+	return results.IP[0]
+}
+
+// active reports if any port on the host was found
+func (results *IPScanResult) active() bool {
+	for _, r := range results.Results {
+		if r.State {
+			return true
+		}
+	}
+	return false
+}
+
+// label returns the service name along with the port state when it is not simply open
+func (r portResult) label() string {
+	if r.Status == "" || r.Status == PortOpen {
+		return r.Service
+	}
+	return fmt.Sprintf("%s (%s)", r.Service, r.Status)
+}
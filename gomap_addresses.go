@@ -0,0 +1,142 @@
+package gomap
+
+import (
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+)
+
+// AddressResult contains the results of one address of a hostname
+// that resolves to several addresses
+type AddressResult struct {
+	IP       net.IP
+	Results  []portResult
+	NetBIOS  *NetBIOSInfo `json:",omitempty"`
+	SMB      *SMBInfo     `json:",omitempty"`
+	SNMP     *SNMPInfo    `json:",omitempty"`
+	Findings []Finding    `json:",omitempty"`
+	Trace    []TraceHop   `json:",omitempty"`
+	OS       []OSMatch    `json:",omitempty"`
+}
+
+// host returns the results of the address as those of a single host
+func (a AddressResult) host(hostname string) *IPScanResult {
+	return &IPScanResult{
+		Hostname: hostname,
+		IP:       []net.IP{a.IP},
+		Address:  a.IP,
+		Results:  a.Results,
+		NetBIOS:  a.NetBIOS,
+		SMB:      a.SMB,
+		SNMP:     a.SNMP,
+		Findings: a.Findings,
+		Trace:    a.Trace,
+		OS:       a.OS,
+	}
+}
+
+// addressResult returns the results of a single address scanned as a host
+func addressResult(host *IPScanResult) AddressResult {
+	return AddressResult{
+		IP:       host.Address,
+		Results:  host.Results,
+		NetBIOS:  host.NetBIOS,
+		SMB:      host.SMB,
+		SNMP:     host.SNMP,
+		Findings: host.Findings,
+		Trace:    host.Trace,
+		OS:       host.OS,
+	}
+}
+
+// PortDifference is a port that is not in the same state on every address of a hostname
+type PortDifference struct {
+	Port    int
+	Service string
+	States  map[string]PortState
+}
+
+// String with the port and its state on each address
+func (d PortDifference) String() string {
+	var ips []string
+	for ip := range d.States {
+		ips = append(ips, ip)
+	}
+	sort.Strings(ips)
+
+	var states []string
+	for _, ip := range ips {
+		states = append(states, fmt.Sprintf("%s on %s", d.States[ip], ip))
+	}
+	return fmt.Sprintf("%d	%s (%s)", d.Port, d.Service, strings.Join(states, ", "))
+}
+
+// Differences returns the ports whose state differs between the addresses
+// of the host. It is empty unless the host was scanned with AllAddresses.
+func (results *IPScanResult) Differences() []PortDifference {
+	if len(results.Addresses) < 2 {
+		return nil
+	}
+
+	ports := make(map[int]*PortDifference)
+	for _, a := range results.Addresses {
+		for _, r := range a.Results {
+			d, ok := ports[r.Port]
+			if !ok {
+				d = &PortDifference{Port: r.Port, Service: r.Service, States: make(map[string]PortState)}
+				ports[r.Port] = d
+			}
+			d.States[a.IP.String()] = addressState(r)
+		}
+	}
+
+	var diffs []PortDifference
+	for _, d := range ports {
+		seen := make(map[PortState]bool)
+		for _, s := range d.States {
+			seen[s] = true
+		}
+		if len(seen) > 1 || len(d.States) != len(results.Addresses) {
+			diffs = append(diffs, *d)
+		}
+	}
+
+	sort.Slice(diffs, func(i, j int) bool {
+		return diffs[i].Port < diffs[j].Port
+	})
+	return diffs
+}
+
+// addressState returns the state of a port result, falling back to the
+// open flag for results recorded before states were tracked
+func addressState(r portResult) PortState {
+	if r.Status != "" {
+		return r.Status
+	}
+	if r.State {
+		return PortOpen
+	}
+	return PortClosed
+}
+
+// mergeAddressResults combines the results of every address so a port
+// is reported as found if it was found on any of them
+func mergeAddressResults(addrs []AddressResult) []portResult {
+	var merged []portResult
+	index := make(map[int]int)
+	for _, a := range addrs {
+		for _, r := range a.Results {
+			i, ok := index[r.Port]
+			if !ok {
+				index[r.Port] = len(merged)
+				merged = append(merged, r)
+				continue
+			}
+			if r.State && !merged[i].State {
+				merged[i] = r
+			}
+		}
+	}
+	return merged
+}
@@ -0,0 +1,148 @@
+package gomap
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"os/user"
+	"sync"
+	"time"
+)
+
+// AuditEvent is a line of the audit log. A "start" or "resume" event is
+// written before a scan sends anything and an "end" event once it is done.
+type AuditEvent struct {
+	Time     time.Time
+	Event    string
+	ScanID   string
+	Operator string
+	// ClaimedOperator is the unverified operator named by a client
+	ClaimedOperator string `json:",omitempty"`
+
+	// Options and Targets are recorded when the scan starts, with secrets
+	// redacted from Options. Targets are the hosts after ranges are expanded.
+	Options *ScanOptions `json:",omitempty"`
+	Targets []string     `json:",omitempty"`
+
+	// The summary is recorded when the scan ends
+	Duration        time.Duration `json:",omitempty"`
+	Hosts           int           `json:",omitempty"`
+	ActiveHosts     int           `json:",omitempty"`
+	OpenPorts       int           `json:",omitempty"`
+	Findings        int           `json:",omitempty"`
+	Vulnerabilities int           `json:",omitempty"`
+	Error           string        `json:",omitempty"`
+}
+
+// auditMu keeps lines from scans in the same process whole
+var auditMu sync.Mutex
+
+// audit records a single scan in ScanOptions.AuditLog
+type audit struct {
+	path     string
+	id       string
+	operator string
+	claimed  string
+	start    time.Time
+}
+
+// startAudit records the start of a scan of targets. It returns nil
+// without an error when auditing is disabled and an error if the start
+// could not be recorded, in which case the scan must not run.
+func startAudit(opts *ScanOptions, event string, targets []string) (*audit, error) {
+	if opts.AuditLog == "" {
+		return nil, nil
+	}
+
+	id := make([]byte, 8)
+	if _, err := rand.Read(id); err != nil {
+		return nil, err
+	}
+	a := &audit{
+		path:     opts.AuditLog,
+		id:       hex.EncodeToString(id),
+		operator: opts.Operator,
+		claimed:  opts.ClaimedOperator,
+		start:    time.Now(),
+	}
+	if a.operator == "" {
+		if u, err := user.Current(); err == nil {
+			a.operator = u.Username
+		}
+	}
+
+	return a, a.write(AuditEvent{
+		Time:            a.start,
+		Event:           event,
+		ScanID:          a.id,
+		Operator:        a.operator,
+		ClaimedOperator: a.claimed,
+		Options:         opts.redacted(),
+		Targets:         targets,
+	})
+}
+
+// finish records the outcome of the scan and returns scanErr, or the
+// error writing the record if the scan itself succeeded
+func (a *audit) finish(results RangeScanResult, scanErr error) error {
+	if a == nil {
+		return scanErr
+	}
+
+	e := AuditEvent{
+		Time:            time.Now(),
+		Event:           "end",
+		ScanID:          a.id,
+		Operator:        a.operator,
+		ClaimedOperator: a.claimed,
+		Duration:        time.Since(a.start),
+	}
+	for _, r := range results {
+		if r == nil {
+			continue
+		}
+		e.Hosts++
+		if r.active() {
+			e.ActiveHosts++
+		}
+		for _, p := range r.Results {
+			if p.State {
+				e.OpenPorts++
+			}
+			e.Vulnerabilities += len(p.Vulns)
+		}
+		e.Findings += len(r.Findings)
+		for _, a := range r.Addresses {
+			e.Findings += len(a.Findings)
+		}
+	}
+	if scanErr != nil {
+		e.Error = scanErr.Error()
+	}
+	if err := a.write(e); scanErr == nil {
+		return err
+	}
+	return scanErr
+}
+
+// write appends e to the log as a single line
+func (a *audit) write(e AuditEvent) error {
+	line, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+
+	auditMu.Lock()
+	defer auditMu.Unlock()
+
+	f, err := os.OpenFile(a.path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
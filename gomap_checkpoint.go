@@ -0,0 +1,185 @@
+package gomap
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"time"
+)
+
+// ErrCommunitiesRequired is returned by ResumeScan when the checkpointed
+// scan queried SNMP and the communities were not passed again
+var ErrCommunitiesRequired = errors.New("snmp communities required to resume")
+
+// checkpoint contains the state of a scan that is written to disk so
+// the scan can be continued with ResumeScan if the process dies
+type checkpoint struct {
+	path     string
+	lastSave time.Time
+
+	Options   ScanOptions
+	Hosts     []string
+	Completed []string
+	Results   RangeScanResult
+	Current   string
+	Partial   []portResult
+	// Addresses holds the finished addresses of AddressHost when it is
+	// scanned with AllAddresses
+	AddressHost string          `json:",omitempty"`
+	Addresses   []AddressResult `json:",omitempty"`
+	Complete    bool
+	Updated     time.Time
+
+	// Domains maps addresses to names for subdomain scans
+	Domains map[string][]string `json:",omitempty"`
+	// MDNS and UPnP hold what discovery found before a range scan by address
+	MDNS map[string][]MDNSService `json:",omitempty"`
+	UPnP map[string][]UPnPDevice  `json:",omitempty"`
+}
+
+// newCheckpoint returns nil when checkpointing is disabled in opts
+func newCheckpoint(opts ScanOptions, hosts []string) *checkpoint {
+	if opts.Checkpoint == "" {
+		return nil
+	}
+	return &checkpoint{
+		path:    opts.Checkpoint,
+		Options: opts,
+		Hosts:   hosts,
+	}
+}
+
+// loadCheckpoint reads a checkpoint previously written by a scan
+func loadCheckpoint(path string) (*checkpoint, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	cp := &checkpoint{}
+	if err := json.Unmarshal(data, cp); err != nil {
+		return nil, err
+	}
+	cp.path = path
+	cp.Options.Checkpoint = path
+	return cp, nil
+}
+
+// save atomically writes the checkpoint to disk. SNMP communities are
+// secrets and only their number is written.
+func (cp *checkpoint) save() error {
+	cp.Updated = time.Now()
+	cp.lastSave = cp.Updated
+
+	c := *cp
+	c.Options = *cp.Options.redacted()
+	data, err := json.Marshal(&c)
+	if err != nil {
+		return err
+	}
+
+	tmp := cp.path + ".tmp"
+	if err := ioutil.WriteFile(tmp, data, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, cp.path)
+}
+
+// remaining returns the hosts that have not been fully scanned
+func (cp *checkpoint) remaining() []string {
+	done := make(map[string]bool, len(cp.Completed))
+	for _, h := range cp.Completed {
+		done[h] = true
+	}
+
+	var hosts []string
+	for _, h := range cp.Hosts {
+		if !done[h] {
+			hosts = append(hosts, h)
+		}
+	}
+	return hosts
+}
+
+// results returns the results of hosts finished before the scan was resumed
+func (cp *checkpoint) results() RangeScanResult {
+	if cp == nil {
+		return nil
+	}
+	return append(RangeScanResult(nil), cp.Results...)
+}
+
+// partial returns the ports already scanned on hostname
+func (cp *checkpoint) partial(hostname string) []portResult {
+	if cp == nil || cp.Current != hostname {
+		return nil
+	}
+	return append([]portResult(nil), cp.Partial...)
+}
+
+// progress records the ports scanned so far on hostname, saving
+// the checkpoint if the checkpoint interval has passed
+func (cp *checkpoint) progress(hostname string, results []portResult) error {
+	if cp == nil {
+		return nil
+	}
+
+	cp.Current = hostname
+	cp.Partial = results
+	if time.Since(cp.lastSave) < cp.Options.CheckpointInterval {
+		return nil
+	}
+	return cp.save()
+}
+
+// hostDone marks hostname as completed. result is nil if the host could not be scanned.
+func (cp *checkpoint) hostDone(hostname string, result *IPScanResult) error {
+	if cp == nil {
+		return nil
+	}
+
+	cp.Completed = append(cp.Completed, hostname)
+	if result != nil {
+		cp.Results = append(cp.Results, result)
+	}
+	cp.Current = ""
+	cp.Partial = nil
+	cp.AddressHost = ""
+	cp.Addresses = nil
+	return cp.save()
+}
+
+// addresses returns the addresses of hostname finished before the scan was resumed
+func (cp *checkpoint) addresses(hostname string) []AddressResult {
+	if cp == nil || cp.AddressHost != hostname {
+		return nil
+	}
+	return append([]AddressResult(nil), cp.Addresses...)
+}
+
+// addressDone records a finished address of hostname
+func (cp *checkpoint) addressDone(hostname string, result AddressResult) error {
+	if cp == nil {
+		return nil
+	}
+
+	if cp.AddressHost != hostname {
+		cp.AddressHost = hostname
+		cp.Addresses = nil
+	}
+	cp.Addresses = append(cp.Addresses, result)
+	cp.Current = ""
+	cp.Partial = nil
+	return cp.save()
+}
+
+// finish marks the scan as complete so resuming returns the stored results
+func (cp *checkpoint) finish() error {
+	if cp == nil {
+		return nil
+	}
+
+	cp.Complete = true
+	return cp.save()
+}
@@ -0,0 +1,248 @@
+package gomap
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Check is a plugin run against scanned hosts and their open ports.
+// Checks are registered with RegisterCheck and enabled by name with
+// ScanOptions.Checks.
+type Check interface {
+	// Name is the unique name the check is registered and enabled under
+	Name() string
+	// Applies reports whether the check should run against target. It is
+	// asked once for the host with Port set to 0 and once for each open port.
+	Applies(target CheckTarget) bool
+	// Run performs the check. It should give up once ctx is done.
+	Run(ctx context.Context, target CheckTarget) ([]Finding, error)
+}
+
+// CheckTarget is the host or port a check is run against
+type CheckTarget struct {
+	// Host is the address to connect to and Hostname its name
+	Host     string
+	Hostname string
+	// Port, Proto, Service and Status describe the port, Port is 0 for host checks
+	Port    int
+	Proto   string
+	Service string
+	Status  PortState
+	// Version is what version detection found on the port, if it ran
+	Version *ServiceVersion
+	// Result is a copy of everything found about the host so far. Checks
+	// must not modify it.
+	Result *IPScanResult
+}
+
+// Address returns the host and port to dial
+func (t CheckTarget) Address() string {
+	return net.JoinHostPort(t.Host, strconv.Itoa(t.Port))
+}
+
+// Severity ranks findings
+type Severity string
+
+const (
+	SeverityInfo     Severity = "info"
+	SeverityLow      Severity = "low"
+	SeverityMedium   Severity = "medium"
+	SeverityHigh     Severity = "high"
+	SeverityCritical Severity = "critical"
+)
+
+// Finding is a result reported by a check
+type Finding struct {
+	Check    string
+	Port     int `json:",omitempty"`
+	Severity Severity
+	Title    string
+	Detail   string            `json:",omitempty"`
+	Data     map[string]string `json:",omitempty"`
+}
+
+// String with the severity, port and title of the finding
+func (f Finding) String() string {
+	s := fmt.Sprintf("[%s] %s: %s", f.Severity, f.Check, f.Title)
+	if f.Port != 0 {
+		s = fmt.Sprintf("[%s] %d %s: %s", f.Severity, f.Port, f.Check, f.Title)
+	}
+	if f.Detail != "" {
+		s += " (" + f.Detail + ")"
+	}
+	return s
+}
+
+var (
+	checksMu sync.RWMutex
+	checks   = make(map[string]Check)
+)
+
+// RegisterCheck makes a check available to scans. Registering a second
+// check with the same name replaces the first.
+func RegisterCheck(c Check) {
+	checksMu.Lock()
+	defer checksMu.Unlock()
+	checks[c.Name()] = c
+}
+
+// RegisteredChecks returns the names of every registered check
+func RegisteredChecks() []string {
+	checksMu.RLock()
+	defer checksMu.RUnlock()
+	return checkNames()
+}
+
+// checkNames returns the sorted names of the registered checks, checksMu must be held
+func checkNames() []string {
+	var names []string
+	for n := range checks {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// enabledChecks returns the registered checks named in names, "all"
+// enables every registered check
+func enabledChecks(names []string) ([]Check, error) {
+	checksMu.RLock()
+	defer checksMu.RUnlock()
+
+	var enabled []Check
+	for _, n := range names {
+		if n == "all" {
+			enabled = nil
+			for _, name := range checkNames() {
+				enabled = append(enabled, checks[name])
+			}
+			return enabled, nil
+		}
+		c, ok := checks[n]
+		if !ok {
+			return nil, fmt.Errorf("unknown check: %s", n)
+		}
+		enabled = append(enabled, c)
+	}
+	return enabled, nil
+}
+
+// runChecks runs the enabled checks against each open port of scan and
+// then against the host, so host checks see the port findings
+func runChecks(target string, scan *IPScanResult, opts *ScanOptions) {
+	enabled, err := enabledChecks(opts.Checks)
+	if err != nil {
+		return
+	}
+
+	// Checks that outlive CheckTimeout are abandoned but keep running, so
+	// they read a snapshot instead of the result that is still being filled
+	snapshot := checkSnapshot(scan)
+	var ports []CheckTarget
+	for _, r := range snapshot.Results {
+		if addressState(r) == PortOpen {
+			ports = append(ports, CheckTarget{
+				Host:     target,
+				Hostname: scan.Hostname,
+				Port:     r.Port,
+				Proto:    serviceProto(opts.Proto),
+				Service:  r.Service,
+				Status:   addressState(r),
+				Version:  r.Version,
+				Result:   snapshot,
+			})
+		}
+	}
+
+	scan.Findings = append(scan.Findings, runCheckTargets(enabled, ports, opts)...)
+
+	host := CheckTarget{Host: target, Hostname: scan.Hostname, Proto: serviceProto(opts.Proto), Result: checkSnapshot(scan)}
+	scan.Findings = append(scan.Findings, runCheckTargets(enabled, []CheckTarget{host}, opts)...)
+}
+
+// checkSnapshot copies scan and the slices checks read from it
+func checkSnapshot(scan *IPScanResult) *IPScanResult {
+	c := *scan
+	c.Results = append([]portResult(nil), scan.Results...)
+	c.Findings = append([]Finding(nil), scan.Findings...)
+	return &c
+}
+
+// runCheckTargets runs each check that applies to each target using a pool
+// of opts.CheckWorkers workers and returns the findings in a stable order
+func runCheckTargets(enabled []Check, targets []CheckTarget, opts *ScanOptions) []Finding {
+	type job struct {
+		check  Check
+		target CheckTarget
+	}
+
+	var jobs []job
+	for _, t := range targets {
+		for _, c := range enabled {
+			if c.Applies(t) {
+				jobs = append(jobs, job{c, t})
+			}
+		}
+	}
+
+	results := make([][]Finding, len(jobs))
+	in := make(chan int)
+	var wg sync.WaitGroup
+	for i := 0; i < opts.CheckWorkers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := range in {
+				results[j] = runCheck(jobs[j].check, jobs[j].target, opts.CheckTimeout)
+			}
+		}()
+	}
+	for j := range jobs {
+		in <- j
+	}
+	close(in)
+	wg.Wait()
+
+	var findings []Finding
+	for _, f := range results {
+		findings = append(findings, f...)
+	}
+	return findings
+}
+
+// runCheck runs a single check, abandoning it if it outlives timeout.
+// Failed checks report nothing.
+func runCheck(c Check, target CheckTarget, timeout time.Duration) []Finding {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	done := make(chan []Finding, 1)
+	go func() {
+		findings, err := c.Run(ctx, target)
+		if err != nil {
+			findings = nil
+		}
+		done <- findings
+	}()
+
+	select {
+	case findings := <-done:
+		for i := range findings {
+			findings[i].Check = c.Name()
+			if findings[i].Port == 0 {
+				findings[i].Port = target.Port
+			}
+			if findings[i].Severity == "" {
+				findings[i].Severity = SeverityInfo
+			}
+		}
+		return findings
+	case <-ctx.Done():
+		return nil
+	}
+}
@@ -0,0 +1,155 @@
+package gomap
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"sort"
+)
+
+// ScanDiff lists what changed between two scans. Hosts scanned by name are
+// matched by name, so a name whose address changes is the same host, and
+// the others by address.
+type ScanDiff struct {
+	// NewHosts and GoneHosts are the hosts with open ports in only one of the scans
+	NewHosts  []string     `json:",omitempty"`
+	GoneHosts []string     `json:",omitempty"`
+	Opened    []PortChange `json:",omitempty"`
+	Closed    []PortChange `json:",omitempty"`
+	// Changed are the ports open in both scans with a different service or version
+	Changed []PortChange `json:",omitempty"`
+}
+
+// PortChange is a port that opened, closed or changed between two scans
+type PortChange struct {
+	Host    string
+	Port    int
+	Service string
+	// Previous is the service the earlier scan found on a changed port
+	Previous string `json:",omitempty"`
+}
+
+// String with the host, port and service of the change
+func (c PortChange) String() string {
+	if c.Previous != "" {
+		return fmt.Sprintf("%s:%d %s (was %s)", c.Host, c.Port, c.Service, c.Previous)
+	}
+	return fmt.Sprintf("%s:%d %s", c.Host, c.Port, c.Service)
+}
+
+// DiffResults compares the open ports of two scans
+func DiffResults(old, new RangeScanResult) ScanDiff {
+	before, after := openPorts(old), openPorts(new)
+
+	var d ScanDiff
+	for host, ports := range after {
+		prev, ok := before[host]
+		if !ok {
+			d.NewHosts = append(d.NewHosts, host)
+		}
+		for port, service := range ports {
+			p, ok := prev[port]
+			switch {
+			case !ok:
+				d.Opened = append(d.Opened, PortChange{Host: host, Port: port, Service: service})
+			case p != service:
+				d.Changed = append(d.Changed, PortChange{Host: host, Port: port, Service: service, Previous: p})
+			}
+		}
+	}
+	for host, ports := range before {
+		if _, ok := after[host]; !ok {
+			d.GoneHosts = append(d.GoneHosts, host)
+		}
+		for port, service := range ports {
+			if _, ok := after[host][port]; !ok {
+				d.Closed = append(d.Closed, PortChange{Host: host, Port: port, Service: service})
+			}
+		}
+	}
+
+	sort.Strings(d.NewHosts)
+	sort.Strings(d.GoneHosts)
+	sortPortChanges(d.Opened)
+	sortPortChanges(d.Closed)
+	sortPortChanges(d.Changed)
+	return d
+}
+
+// Empty reports if nothing changed
+func (d ScanDiff) Empty() bool {
+	return len(d.NewHosts) == 0 && len(d.GoneHosts) == 0 &&
+		len(d.Opened) == 0 && len(d.Closed) == 0 && len(d.Changed) == 0
+}
+
+// Len is the number of changes
+func (d ScanDiff) Len() int {
+	return len(d.NewHosts) + len(d.GoneHosts) + len(d.Opened) + len(d.Closed) + len(d.Changed)
+}
+
+// String with a line per change, marked + when added, - when removed
+// and ~ when changed
+func (d ScanDiff) String() string {
+	b := bytes.NewBuffer(nil)
+	for _, h := range d.NewHosts {
+		fmt.Fprintf(b, "+ Host: %s\n", h)
+	}
+	for _, h := range d.GoneHosts {
+		fmt.Fprintf(b, "- Host: %s\n", h)
+	}
+	for _, c := range d.Opened {
+		fmt.Fprintf(b, "+ %s\n", c)
+	}
+	for _, c := range d.Closed {
+		fmt.Fprintf(b, "- %s\n", c)
+	}
+	for _, c := range d.Changed {
+		fmt.Fprintf(b, "~ %s\n", c)
+	}
+	return b.String()
+}
+
+// openPorts maps each host with open ports to its open ports and what
+// was found on them
+func openPorts(results RangeScanResult) map[string]map[int]string {
+	hosts := make(map[string]map[int]string)
+	for _, r := range results {
+		if r == nil || r.diffKey() == "" || !r.active() {
+			continue
+		}
+		ports := make(map[int]string)
+		for _, p := range r.Results {
+			if !p.State {
+				continue
+			}
+			ports[p.Port] = p.label()
+			if p.Version != nil {
+				ports[p.Port] = p.Version.String()
+			}
+		}
+		hosts[r.diffKey()] = ports
+	}
+	return hosts
+}
+
+// diffKey identifies the host across scans, the name it was scanned as
+// or else the address that was scanned
+func (results *IPScanResult) diffKey() string {
+	if results.Target != "" && net.ParseIP(results.Target) == nil {
+		return results.Target
+	}
+	if ip := results.address(); ip != nil {
+		return ip.String()
+	}
+	return ""
+}
+
+// sortPortChanges orders changes by host and port
+func sortPortChanges(changes []PortChange) {
+	sort.Slice(changes, func(i, j int) bool {
+		if changes[i].Host != changes[j].Host {
+			return changes[i].Host < changes[j].Host
+		}
+		return changes[i].Port < changes[j].Port
+	})
+}
@@ -0,0 +1,276 @@
+package gomap
+
+import (
+	"context"
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// HostName is a name found by a reverse lookup of a scanned address.
+// Confirmed is true when the name resolves back to the same address
+// (forward-confirmed reverse DNS).
+type HostName struct {
+	Name      string
+	Confirmed bool
+}
+
+// String returns the name, marked if it is not confirmed
+func (h HostName) String() string {
+	if h.Confirmed {
+		return h.Name
+	}
+	return h.Name + " (unconfirmed)"
+}
+
+// Resolver performs and caches the DNS lookups used by scans. The zero
+// value uses the system resolver and the default timeout and TTLs.
+type Resolver struct {
+	// Servers are the DNS servers queried in turn, as "host" or
+	// "host:port". The system resolver is used when empty.
+	Servers []string
+	Timeout time.Duration
+	// CacheTTL is how long answers are cached and FailureTTL how long
+	// failed lookups are
+	CacheTTL   time.Duration
+	FailureTTL time.Duration
+
+	once     sync.Once
+	resolver *net.Resolver
+	next     uint32
+
+	mu      sync.Mutex
+	reverse map[string]lookupResult
+	forward map[string]lookupResult
+	// names are the names of addresses found by other means than DNS
+	names map[string][]string
+}
+
+// lookupResult is a cached answer, failures are cached as well so
+// hosts without records are not queried again until they expire
+type lookupResult struct {
+	names   []string
+	ips     []net.IP
+	err     error
+	expires time.Time
+}
+
+// NewResolver returns a Resolver that queries servers, or the system
+// resolver if none are provided
+func NewResolver(servers ...string) *Resolver {
+	r := &Resolver{Servers: servers}
+	r.setup()
+	return r
+}
+
+// setup fills in the defaults on first use so the zero value works. The
+// fields must not be changed once the resolver is in use.
+func (r *Resolver) setup() {
+	r.once.Do(func() {
+		if r.Timeout <= 0 {
+			r.Timeout = 5 * time.Second
+		}
+		if r.CacheTTL <= 0 {
+			r.CacheTTL = 5 * time.Minute
+		}
+		if r.FailureTTL <= 0 {
+			r.FailureTTL = 30 * time.Second
+		}
+		r.reverse = make(map[string]lookupResult)
+		r.forward = make(map[string]lookupResult)
+		r.names = make(map[string][]string)
+
+		var servers []string
+		for _, s := range r.Servers {
+			if _, _, err := net.SplitHostPort(s); err != nil {
+				s = net.JoinHostPort(s, "53")
+			}
+			servers = append(servers, s)
+		}
+		r.Servers = servers
+
+		r.resolver = net.DefaultResolver
+		if len(r.Servers) > 0 {
+			r.resolver = &net.Resolver{PreferGo: true, Dial: r.dial}
+		}
+	})
+}
+
+// dial connects to the next server so queries are spread over all of them
+func (r *Resolver) dial(ctx context.Context, network, address string) (net.Conn, error) {
+	n := atomic.AddUint32(&r.next, 1)
+	d := net.Dialer{Timeout: r.Timeout}
+	return d.DialContext(ctx, network, r.Servers[int(n)%len(r.Servers)])
+}
+
+// LookupIP returns the addresses of host
+func (r *Resolver) LookupIP(host string) ([]net.IP, error) {
+	r.setup()
+	if ip := net.ParseIP(host); ip != nil {
+		return []net.IP{ip}, nil
+	}
+
+	res, ok := r.cached(r.forward, host)
+	if ok {
+		return res.ips, res.err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), r.Timeout)
+	defer cancel()
+	addrs, err := r.resolver.LookupIPAddr(ctx, host)
+	for _, a := range addrs {
+		res.ips = append(res.ips, a.IP)
+	}
+	res.err = err
+
+	r.store(r.forward, host, res)
+	return res.ips, res.err
+}
+
+// LookupAddr returns every PTR name of ip
+func (r *Resolver) LookupAddr(ip string) ([]string, error) {
+	r.setup()
+	res, ok := r.cached(r.reverse, ip)
+	if ok {
+		return res.names, res.err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), r.Timeout)
+	defer cancel()
+	res.names, res.err = r.resolver.LookupAddr(ctx, ip)
+
+	r.store(r.reverse, ip, res)
+	return res.names, res.err
+}
+
+// cached returns the answer cached for key in cache if it has not expired
+func (r *Resolver) cached(cache map[string]lookupResult, key string) (lookupResult, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	res, ok := cache[key]
+	if ok && time.Now().After(res.expires) {
+		delete(cache, key)
+		return lookupResult{}, false
+	}
+	return res, ok
+}
+
+// store caches res for key in cache until its TTL runs out
+func (r *Resolver) store(cache map[string]lookupResult, key string, res lookupResult) {
+	ttl := r.CacheTTL
+	if res.err != nil {
+		ttl = r.FailureTTL
+	}
+	res.expires = time.Now().Add(ttl)
+
+	r.mu.Lock()
+	cache[key] = res
+	r.mu.Unlock()
+}
+
+// LookupNS returns the name servers of domain
+func (r *Resolver) LookupNS(domain string) ([]string, error) {
+	r.setup()
+	ctx, cancel := context.WithTimeout(context.Background(), r.Timeout)
+	defer cancel()
+	ns, err := r.resolver.LookupNS(ctx, domain)
+	if err != nil {
+		return nil, err
+	}
+
+	var hosts []string
+	for _, n := range ns {
+		hosts = append(hosts, n.Host)
+	}
+	return hosts, nil
+}
+
+// addNames records names found by other means, such as mDNS, for ip so
+// they are returned with its PTR names. They are only confirmed if they
+// also resolve back to ip over DNS.
+func (r *Resolver) addNames(ip string, names []string) {
+	r.setup()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, n := range names {
+		if !containsString(r.names[ip], n) {
+			r.names[ip] = append(r.names[ip], n)
+		}
+	}
+}
+
+// addHostNames records the names of each address with addNames
+func (r *Resolver) addHostNames(hosts map[string][]string) {
+	for ip, names := range hosts {
+		r.addNames(ip, names)
+	}
+}
+
+// HostNames returns the PTR names of ip and those added for it, checking that each one resolves back to ip
+func (r *Resolver) HostNames(ip string) ([]HostName, error) {
+	r.setup()
+	names, err := r.LookupAddr(ip)
+
+	r.mu.Lock()
+	added := r.names[ip]
+	r.mu.Unlock()
+	if err != nil && len(added) == 0 {
+		return nil, err
+	}
+	names = append([]string(nil), names...)
+	for _, n := range added {
+		if !containsString(names, n) {
+			names = append(names, n)
+		}
+	}
+
+	addr := net.ParseIP(ip)
+	var hosts []HostName
+	for _, n := range names {
+		h := HostName{Name: n}
+		ips, err := r.LookupIP(n)
+		if err == nil {
+			for _, i := range ips {
+				if i.Equal(addr) {
+					h.Confirmed = true
+					break
+				}
+			}
+		}
+		hosts = append(hosts, h)
+	}
+	return hosts, nil
+}
+
+// Resolve looks up the names of every address in parallel so they are
+// cached before the addresses are scanned
+func (r *Resolver) Resolve(ips []string, workers int) {
+	in := make(chan string)
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for ip := range in {
+				r.HostNames(ip)
+			}
+		}()
+	}
+
+	for _, ip := range ips {
+		in <- ip
+	}
+	close(in)
+	wg.Wait()
+}
+
+// preferredName picks the name shown for a host, confirmed names first
+func preferredName(names []HostName) string {
+	for _, n := range names {
+		if n.Confirmed {
+			return n.Name
+		}
+	}
+	return names[0].Name
+}
@@ -3,9 +3,7 @@ package gomap
 import (
 	"encoding/binary"
 	"fmt"
-	"log"
 	"net"
-	"strings"
 )
 
 func canSocketBind(laddr string) bool {
@@ -25,45 +23,98 @@ func canSocketBind(laddr string) bool {
 }
 
 // createHostRange converts a input ip addr string to a slice of ips on the cidr
-func createHostRange(netw string) []string {
+func createHostRange(netw string) ([]string, error) {
 	_, ipv4Net, err := net.ParseCIDR(netw)
 	if err != nil {
-		log.Fatal(err)
+		return nil, err
+	}
+	if ipv4Net.IP.To4() == nil {
+		return nil, fmt.Errorf("%s is not an ipv4 range", netw)
 	}
 
 	mask := binary.BigEndian.Uint32(ipv4Net.Mask)
-	start := binary.BigEndian.Uint32(ipv4Net.IP)
+	start := binary.BigEndian.Uint32(ipv4Net.IP.To4())
 	finish := (start & mask) | (mask ^ 0xffffffff)
 
+	// /31 and /32 have no network or broadcast address to skip
+	if ones, _ := ipv4Net.Mask.Size(); ones < 31 {
+		start++
+		finish--
+	}
+
 	var hosts []string
-	for i := start + 1; i <= finish-1; i++ {
+	for i := start; i <= finish && i >= start; i++ {
 		ip := make(net.IP, 4)
 		binary.BigEndian.PutUint32(ip, i)
 		hosts = append(hosts, ip.String())
 	}
 
-	return hosts
+	return hosts, nil
 }
 
-// getLocalRange returns local ip range or defaults on error to most common
-func getLocalRange() string {
-	addrs, err := net.InterfaceAddrs()
+// rangeSize returns the number of hosts createHostRange returns for netw
+func rangeSize(netw string) (int, error) {
+	_, ipv4Net, err := net.ParseCIDR(netw)
 	if err != nil {
-		return "192.168.1.0/24"
+		return 0, err
 	}
-	for _, address := range addrs {
-		// check the address type and if it is not a loopback the display it
-		if ipnet, ok := address.(*net.IPNet); ok && !ipnet.IP.IsLoopback() {
-			if ipnet.IP.To4() != nil {
-				split := strings.Split(ipnet.IP.String(), ".")
-				return split[0] + "." + split[1] + "." + split[2] + ".0/24"
+
+	ones, bits := ipv4Net.Mask.Size()
+	size := 1 << uint(bits-ones)
+	if ones < 31 {
+		size -= 2
+	}
+	return size, nil
+}
+
+// getLocalRanges returns the cidr ranges attached to this host. Only the range
+// of laddr is returned unless opts.AllSubnets is set, in which case every ipv4
+// range on the chosen interface or every non-loopback interface is returned.
+// laddr is the source address of the default route unless SourceIP or
+// Interface are set, so the range is that of the default route interface.
+func getLocalRanges(opts *ScanOptions, laddr string) ([]string, error) {
+	ifaces, err := Interfaces()
+	if err != nil {
+		return nil, err
+	}
+
+	src := net.ParseIP(laddr)
+	seen := make(map[string]bool)
+	var ranges []string
+	for _, iface := range ifaces {
+		if opts.AllSubnets {
+			if opts.Interface != "" && iface.Name != opts.Interface {
+				continue
+			}
+			if opts.Interface == "" && iface.Flags&net.FlagLoopback != 0 {
+				continue
+			}
+		}
+
+		for _, a := range iface.Addrs {
+			if a.IP.To4() == nil {
+				continue
+			}
+			if !opts.AllSubnets && !a.IP.Equal(src) {
+				continue
+			}
+
+			// Use the real prefix of the address rather than assuming a /24
+			cidr := (&net.IPNet{IP: a.IP.Mask(a.Mask), Mask: a.Mask}).String()
+			if !seen[cidr] {
+				seen[cidr] = true
+				ranges = append(ranges, cidr)
 			}
 		}
 	}
-	return "192.168.1.0/24"
+
+	if len(ranges) == 0 {
+		return nil, fmt.Errorf("no local ipv4 range found for %s", laddr)
+	}
+	return ranges, nil
 }
 
-// getLocalRange returns local ip range or defaults on error to most common
+// getLocalIP returns the first non-loopback ipv4 address
 func getLocalIP() (string, error) {
 	addrs, err := net.InterfaceAddrs()
 	if err != nil {
@@ -0,0 +1,105 @@
+package gomap
+
+import (
+	"fmt"
+	"net"
+)
+
+// Interface describes a local network interface that can be scanned from
+type Interface struct {
+	Name         string
+	Index        int
+	MTU          int
+	Flags        net.Flags
+	HardwareAddr net.HardwareAddr
+	Addrs        []net.IPNet
+}
+
+// Interfaces lists the local interfaces that are up and have an address
+func Interfaces() ([]Interface, error) {
+	ifaces, err := net.Interfaces()
+	if err != nil {
+		return nil, err
+	}
+
+	var usable []Interface
+	for _, iface := range ifaces {
+		if iface.Flags&net.FlagUp == 0 {
+			continue
+		}
+
+		addrs, err := iface.Addrs()
+		if err != nil {
+			return nil, err
+		}
+
+		i := Interface{
+			Name:         iface.Name,
+			Index:        iface.Index,
+			MTU:          iface.MTU,
+			Flags:        iface.Flags,
+			HardwareAddr: iface.HardwareAddr,
+		}
+		for _, a := range addrs {
+			if ipnet, ok := a.(*net.IPNet); ok {
+				i.Addrs = append(i.Addrs, *ipnet)
+			}
+		}
+		if len(i.Addrs) > 0 {
+			usable = append(usable, i)
+		}
+	}
+	return usable, nil
+}
+
+// localAddress returns the source address to scan from. An explicit
+// SourceIP or Interface in opts is used over the address of the default route.
+func localAddress(opts *ScanOptions) (string, error) {
+	if opts.SourceIP == "" && opts.Interface == "" {
+		return sourceAddress(defaultRouteTarget)
+	}
+
+	ifaces, err := Interfaces()
+	if err != nil {
+		return "", err
+	}
+
+	var src net.IP
+	if opts.SourceIP != "" {
+		src = net.ParseIP(opts.SourceIP)
+		if src == nil || src.To4() == nil {
+			return "", fmt.Errorf("invalid source ip: %s", opts.SourceIP)
+		}
+	}
+
+	for _, iface := range ifaces {
+		if opts.Interface != "" && iface.Name != opts.Interface {
+			continue
+		}
+		for _, a := range iface.Addrs {
+			if a.IP.To4() == nil {
+				continue
+			}
+			if src == nil || a.IP.Equal(src) {
+				return a.IP.String(), nil
+			}
+		}
+	}
+
+	if opts.Interface != "" && src != nil {
+		return "", fmt.Errorf("%s is not assigned to interface %s", opts.SourceIP, opts.Interface)
+	}
+	if opts.Interface != "" {
+		return "", fmt.Errorf("no ipv4 address found on interface %s", opts.Interface)
+	}
+	return "", fmt.Errorf("%s is not assigned to a local interface", opts.SourceIP)
+}
+
+// localAddr returns laddr as a net.Addr to bind connections for proto to
+func localAddr(proto string, laddr string) net.Addr {
+	ip := net.ParseIP(laddr)
+	if serviceProto(proto) == "udp" {
+		return &net.UDPAddr{IP: ip}
+	}
+	return &net.TCPAddr{IP: ip}
+}
@@ -0,0 +1,244 @@
+package gomap
+
+import (
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/JustinTimperio/gomap/packet"
+)
+
+// MDNSService is a service instance announced over multicast DNS
+type MDNSService struct {
+	// Instance is the full name such as "Office._ipp._tcp.local."
+	Instance string
+	// Service is the service type such as "_ipp._tcp"
+	Service string
+	Host    string
+	Port    int
+	IPs     []net.IP
+	Text    []string
+}
+
+// String with the instance, host and port
+func (s MDNSService) String() string {
+	name := strings.TrimSuffix(strings.TrimSuffix(s.Instance, "."+s.Service+".local."), ".")
+	str := fmt.Sprintf("%s (%s) %s:%d", name, s.Service, strings.TrimSuffix(s.Host, "."), s.Port)
+	if len(s.Text) > 0 {
+		str += " [" + strings.Join(s.Text, " ") + "]"
+	}
+	return str
+}
+
+// mdnsAddr is the ipv4 mDNS group
+var mdnsAddr = &net.UDPAddr{IP: net.IPv4(224, 0, 0, 251), Port: 5353}
+
+// DiscoverMDNS lists the DNS-SD services announced on the local network,
+// waiting up to timeout for each round of answers
+func DiscoverMDNS(timeout time.Duration) ([]MDNSService, error) {
+	return discoverMDNS("", timeout)
+}
+
+// mdnsRecords gathers the records of every answer to the queries
+type mdnsRecords struct {
+	types     map[string]bool
+	instances map[string]bool
+	srv       map[string]packet.DNSResource
+	txt       map[string][]string
+	addrs     map[string][]net.IP
+}
+
+// discoverMDNS sends one-shot queries from laddr so answers come back
+// by unicast without needing to bind port 5353
+func discoverMDNS(laddr string, timeout time.Duration) ([]MDNSService, error) {
+	conn, err := net.ListenUDP("udp4", &net.UDPAddr{IP: net.ParseIP(laddr)})
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	recs := newMDNSRecords()
+
+	// Service types, then their instances, then the services and
+	// addresses the responders left out of the additional records
+	if err := mdnsQuery(conn, recs, timeout, mdnsQuestion("_services._dns-sd._udp.local.", packet.DNSTypePTR)); err != nil {
+		return nil, err
+	}
+
+	var questions []packet.DNSQuestion
+	for t := range recs.types {
+		questions = append(questions, mdnsQuestion(t, packet.DNSTypePTR))
+	}
+	if err := mdnsQuery(conn, recs, timeout, questions...); err != nil {
+		return nil, err
+	}
+
+	questions = nil
+	for i := range recs.instances {
+		if _, ok := recs.srv[i]; !ok {
+			questions = append(questions, mdnsQuestion(i, packet.DNSTypeSRV), mdnsQuestion(i, packet.DNSTypeTXT))
+		}
+	}
+	if err := mdnsQuery(conn, recs, timeout, questions...); err != nil {
+		return nil, err
+	}
+
+	questions = nil
+	for _, s := range recs.srv {
+		if len(recs.addrs[strings.ToLower(s.Target)]) == 0 {
+			questions = append(questions, mdnsQuestion(s.Target, packet.DNSTypeA))
+		}
+	}
+	if err := mdnsQuery(conn, recs, timeout, questions...); err != nil {
+		return nil, err
+	}
+
+	return recs.services(), nil
+}
+
+// newMDNSRecords returns an empty set of records
+func newMDNSRecords() *mdnsRecords {
+	return &mdnsRecords{
+		types:     make(map[string]bool),
+		instances: make(map[string]bool),
+		srv:       make(map[string]packet.DNSResource),
+		txt:       make(map[string][]string),
+		addrs:     make(map[string][]net.IP),
+	}
+}
+
+// services joins the records of each instance, ordered by instance name
+func (recs *mdnsRecords) services() []MDNSService {
+	var services []MDNSService
+	for i := range recs.instances {
+		s := MDNSService{Instance: i, Text: recs.txt[i]}
+		if j := strings.Index(i, "._"); j >= 0 {
+			s.Service = strings.TrimSuffix(i[j+1:], ".local.")
+		}
+		if srv, ok := recs.srv[i]; ok {
+			s.Host = srv.Target
+			s.Port = int(srv.Port)
+			s.IPs = recs.addrs[strings.ToLower(srv.Target)]
+		}
+		services = append(services, s)
+	}
+	sort.Slice(services, func(i, j int) bool {
+		return services[i].Instance < services[j].Instance
+	})
+	return services
+}
+
+// mdnsQuestion asks for a unicast response to name
+func mdnsQuestion(name string, qtype uint16) packet.DNSQuestion {
+	return packet.DNSQuestion{Name: name, Type: qtype, Class: packet.DNSClassINET | 0x8000}
+}
+
+// mdnsQuery sends the questions and records the answers received before timeout
+func mdnsQuery(conn *net.UDPConn, recs *mdnsRecords, timeout time.Duration, questions ...packet.DNSQuestion) error {
+	if len(questions) == 0 {
+		return nil
+	}
+
+	// Keep each message well under the usual MTU
+	for len(questions) > 0 {
+		n := len(questions)
+		if n > 16 {
+			n = 16
+		}
+		msg, err := (&packet.DNS{Questions: questions[:n]}).Marshal()
+		if err != nil {
+			return err
+		}
+		if _, err := conn.WriteTo(msg, mdnsAddr); err != nil {
+			return err
+		}
+		questions = questions[n:]
+	}
+
+	conn.SetReadDeadline(time.Now().Add(timeout))
+	buff := make([]byte, 9000)
+	for {
+		n, _, err := conn.ReadFrom(buff)
+		if err != nil {
+			if ne, ok := err.(net.Error); ok && ne.Timeout() {
+				return nil
+			}
+			return err
+		}
+
+		// Records point into the message so each one needs its own buffer
+		resp, err := packet.ParseDNS(append([]byte{}, buff[:n]...))
+		if err != nil || resp.Flags&packet.DNSResponse == 0 {
+			continue
+		}
+		for _, r := range append(resp.Answers, resp.Additionals...) {
+			recs.add(r)
+		}
+	}
+}
+
+// add stores a record from a response
+func (recs *mdnsRecords) add(r packet.DNSResource) {
+	name := strings.ToLower(r.Name)
+	switch r.Type {
+	case packet.DNSTypePTR:
+		if name == "_services._dns-sd._udp.local." {
+			recs.types[r.Target] = true
+		} else if strings.HasPrefix(name, "_") {
+			recs.instances[r.Target] = true
+		}
+	case packet.DNSTypeSRV:
+		recs.srv[r.Name] = r
+		recs.instances[r.Name] = true
+	case packet.DNSTypeTXT:
+		var text []string
+		for _, t := range r.Text {
+			if t != "" {
+				text = append(text, t)
+			}
+		}
+		recs.txt[r.Name] = text
+	case packet.DNSTypeA, packet.DNSTypeAAAA:
+		for _, ip := range recs.addrs[name] {
+			if ip.Equal(r.IP) {
+				return
+			}
+		}
+		recs.addrs[name] = append(recs.addrs[name], r.IP)
+	}
+}
+
+// mdnsHosts groups services by the ipv4 addresses of their hosts
+func mdnsHosts(services []MDNSService) map[string][]MDNSService {
+	hosts := make(map[string][]MDNSService)
+	for _, s := range services {
+		for _, ip := range s.IPs {
+			if ip.To4() != nil {
+				hosts[ip.String()] = append(hosts[ip.String()], s)
+			}
+		}
+	}
+	return hosts
+}
+
+// mdnsNames maps each host address to the names it announced
+func mdnsNames(hosts map[string][]MDNSService) map[string][]string {
+	names := make(map[string][]string)
+	for ip, services := range hosts {
+		for _, s := range services {
+			names[ip] = append(names[ip], s.Host)
+		}
+	}
+	return names
+}
+
+// addMDNS attaches the discovered services to the results of their hosts
+func addMDNS(results RangeScanResult, hosts map[string][]MDNSService) {
+	for _, r := range results {
+		if ip := r.address(); ip != nil {
+			r.MDNS = hosts[ip.String()]
+		}
+	}
+}
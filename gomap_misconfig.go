@@ -0,0 +1,429 @@
+package gomap
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// serviceCheck is a built-in check run against open ports of a service,
+// open ports whose detected product is one of products, and its default
+// ports whatever they were named.
+type serviceCheck struct {
+	name     string
+	services []string
+	products []string
+	ports    []int
+	run      func(ctx context.Context, t CheckTarget) ([]Finding, error)
+}
+
+func (c serviceCheck) Name() string {
+	return c.name
+}
+
+func (c serviceCheck) Applies(t CheckTarget) bool {
+	if t.Port == 0 || t.Proto != "tcp" {
+		return false
+	}
+	if containsString(c.services, t.Service) || c.matchProduct(t.Version) {
+		return true
+	}
+	for _, p := range c.ports {
+		if p == t.Port {
+			return true
+		}
+	}
+	return false
+}
+
+// matchProduct reports if the product or a CPE of v is one of products.
+// Version detection names services after the protocol, so Elasticsearch
+// and Docker are found as http.
+func (c serviceCheck) matchProduct(v *ServiceVersion) bool {
+	if v == nil {
+		return false
+	}
+	product := strings.ToLower(v.Product)
+	for _, p := range c.products {
+		if strings.Contains(product, p) {
+			return true
+		}
+	}
+	for _, cpe := range v.CPEs() {
+		if containsString(c.products, strings.ToLower(cpe.Product)) {
+			return true
+		}
+	}
+	return false
+}
+
+func (c serviceCheck) Run(ctx context.Context, t CheckTarget) ([]Finding, error) {
+	return c.run(ctx, t)
+}
+
+// The built-in checks only read what an unauthenticated client is shown
+// and never change anything on the target
+func init() {
+	RegisterCheck(serviceCheck{"ftp-anon", []string{"ftp"}, nil, []int{21}, checkFTPAnonymous})
+	RegisterCheck(serviceCheck{"redis-noauth", []string{"redis"}, []string{"redis"}, []int{6379}, checkRedis})
+	RegisterCheck(serviceCheck{"memcached-noauth", []string{"memcache", "memcached"}, []string{"memcached"}, []int{11211}, checkMemcached})
+	RegisterCheck(serviceCheck{"elasticsearch-noauth", []string{"elasticsearch"}, []string{"elasticsearch"}, []int{9200}, checkElasticsearch})
+	RegisterCheck(serviceCheck{"mongodb-noauth", []string{"mongodb"}, []string{"mongodb"}, []int{27017}, checkMongoDB})
+	RegisterCheck(serviceCheck{"docker-api", []string{"docker"}, []string{"docker"}, []int{2375}, checkDockerAPI})
+	RegisterCheck(serviceCheck{"http-dirlist", []string{"http", "https", "http-alt", "http-proxy", "https-alt"}, nil, []int{80, 443, 8000, 8080, 8443}, checkDirectoryListing})
+}
+
+// dialCheck connects to the target port, limited by the deadline of ctx
+func dialCheck(ctx context.Context, t CheckTarget) (net.Conn, error) {
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", t.Address())
+	if err != nil {
+		return nil, err
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+	return conn, nil
+}
+
+// checkFTPAnonymous tries to log in as anonymous
+func checkFTPAnonymous(ctx context.Context, t CheckTarget) ([]Finding, error) {
+	conn, err := dialCheck(ctx, t)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+
+	banner, err := readFTPReply(r)
+	if err != nil || !strings.HasPrefix(banner, "220") {
+		return nil, err
+	}
+
+	fmt.Fprintf(conn, "USER anonymous\r\n")
+	reply, err := readFTPReply(r)
+	if err != nil {
+		return nil, err
+	}
+	if strings.HasPrefix(reply, "331") {
+		fmt.Fprintf(conn, "PASS anonymous@example.com\r\n")
+		if reply, err = readFTPReply(r); err != nil {
+			return nil, err
+		}
+	}
+	fmt.Fprintf(conn, "QUIT\r\n")
+
+	if !strings.HasPrefix(reply, "230") {
+		return nil, nil
+	}
+	return []Finding{{
+		Severity: SeverityMedium,
+		Title:    "Anonymous FTP login allowed",
+		Detail:   reply,
+		Data:     map[string]string{"banner": banner},
+	}}, nil
+}
+
+// readFTPReply reads a possibly multi-line reply and returns its last line
+func readFTPReply(r *bufio.Reader) (string, error) {
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return "", err
+		}
+		line = strings.TrimRight(line, "\r\n")
+		if len(line) < 4 || line[3] != '-' {
+			return line, nil
+		}
+	}
+}
+
+// checkRedis asks for the server information, which needs no
+// credentials unless a password is set
+func checkRedis(ctx context.Context, t CheckTarget) ([]Finding, error) {
+	conn, err := dialCheck(ctx, t)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	if _, err := conn.Write([]byte("INFO server\r\n")); err != nil {
+		return nil, err
+	}
+	r := bufio.NewReader(conn)
+	header, err := r.ReadString('\n')
+	if err != nil || !strings.HasPrefix(header, "$") {
+		return nil, err
+	}
+	size, err := strconv.Atoi(strings.TrimSpace(header[1:]))
+	if err != nil || size < 0 || size > 1<<20 {
+		return nil, errors.New("redis: invalid reply")
+	}
+	body := make([]byte, size)
+	if _, err := io.ReadFull(r, body); err != nil {
+		return nil, err
+	}
+
+	info := make(map[string]string)
+	for _, line := range strings.Split(string(body), "\r\n") {
+		if kv := strings.SplitN(line, ":", 2); len(kv) == 2 {
+			info[kv[0]] = kv[1]
+		}
+	}
+	if info["redis_version"] == "" {
+		return nil, nil
+	}
+	return []Finding{{
+		Severity: SeverityHigh,
+		Title:    "Redis accepts commands without authentication",
+		Detail:   "INFO returned redis_version " + info["redis_version"],
+		Data: map[string]string{
+			"redis_version": info["redis_version"],
+			"os":            info["os"],
+			"redis_mode":    info["redis_mode"],
+		},
+	}}, nil
+}
+
+// checkMemcached asks for the server statistics
+func checkMemcached(ctx context.Context, t CheckTarget) ([]Finding, error) {
+	conn, err := dialCheck(ctx, t)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	if _, err := conn.Write([]byte("stats\r\n")); err != nil {
+		return nil, err
+	}
+	stats := make(map[string]string)
+	r := bufio.NewReader(conn)
+	for len(stats) < 1000 {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		fields := strings.Fields(line)
+		if len(fields) == 1 && fields[0] == "END" {
+			break
+		}
+		if len(fields) != 3 || fields[0] != "STAT" {
+			return nil, nil
+		}
+		stats[fields[1]] = fields[2]
+	}
+	if len(stats) == 0 {
+		return nil, nil
+	}
+	return []Finding{{
+		Severity: SeverityMedium,
+		Title:    "Memcached accepts commands without authentication",
+		Detail:   fmt.Sprintf("stats returned %d values", len(stats)),
+		Data: map[string]string{
+			"version":    stats["version"],
+			"curr_items": stats["curr_items"],
+		},
+	}}, nil
+}
+
+// checkElasticsearch reads the cluster information
+func checkElasticsearch(ctx context.Context, t CheckTarget) ([]Finding, error) {
+	resp, body, err := checkGet(ctx, t, "/")
+	if err != nil || resp.StatusCode != http.StatusOK {
+		return nil, err
+	}
+
+	var info struct {
+		Name        string `json:"name"`
+		ClusterName string `json:"cluster_name"`
+		Version     struct {
+			Number string `json:"number"`
+		} `json:"version"`
+	}
+	if err := json.Unmarshal(body, &info); err != nil || info.ClusterName == "" {
+		return nil, nil
+	}
+	return []Finding{{
+		Severity: SeverityHigh,
+		Title:    "Elasticsearch API accessible without authentication",
+		Detail:   "GET / returned cluster " + info.ClusterName,
+		Data: map[string]string{
+			"name":         info.Name,
+			"cluster_name": info.ClusterName,
+			"version":      info.Version.Number,
+		},
+	}}, nil
+}
+
+// checkMongoDB lists the databases, which fails when authentication is enabled
+func checkMongoDB(ctx context.Context, t CheckTarget) ([]Finding, error) {
+	conn, err := dialCheck(ctx, t)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	// {listDatabases: 1, nameOnly: true, $db: "admin"} in an OP_MSG
+	var doc []byte
+	doc = append(doc, 0x10)
+	doc = append(doc, "listDatabases\x00"...)
+	doc = append(doc, 1, 0, 0, 0)
+	doc = append(doc, 0x08)
+	doc = append(doc, "nameOnly\x00"...)
+	doc = append(doc, 1)
+	doc = append(doc, 0x02)
+	doc = append(doc, "$db\x00"...)
+	doc = append(doc, 6, 0, 0, 0)
+	doc = append(doc, "admin\x00"...)
+	doc = append(doc, 0)
+	size := make([]byte, 4)
+	binary.LittleEndian.PutUint32(size, uint32(len(doc)+4))
+	doc = append(size, doc...)
+
+	msg := make([]byte, 21)
+	binary.LittleEndian.PutUint32(msg[0:4], uint32(len(msg)+len(doc)))
+	binary.LittleEndian.PutUint32(msg[4:8], 1)
+	binary.LittleEndian.PutUint32(msg[12:16], 2013)
+	msg = append(msg, doc...)
+	if _, err := conn.Write(msg); err != nil {
+		return nil, err
+	}
+
+	if _, err := io.ReadFull(conn, size); err != nil {
+		return nil, err
+	}
+	n := binary.LittleEndian.Uint32(size)
+	if n < 21 || n > 16<<20 {
+		return nil, errors.New("mongodb: invalid reply")
+	}
+	reply := make([]byte, n-4)
+	if _, err := io.ReadFull(conn, reply); err != nil {
+		return nil, err
+	}
+
+	// Databases are listed under names, the reply only says ok on success
+	if !bytes.Contains(reply, []byte("databases\x00")) || bytes.Contains(reply, []byte("errmsg\x00")) {
+		return nil, nil
+	}
+	names := regexp.MustCompile(`(?s)\x02name\x00.\x00\x00\x00([^\x00]*)\x00`).FindAllSubmatch(reply, -1)
+	var dbs []string
+	for _, n := range names {
+		dbs = append(dbs, string(n[1]))
+	}
+	return []Finding{{
+		Severity: SeverityHigh,
+		Title:    "MongoDB accessible without authentication",
+		Detail:   "listDatabases succeeded without credentials",
+		Data:     map[string]string{"databases": strings.Join(dbs, ",")},
+	}}, nil
+}
+
+// checkDockerAPI reads the engine version from the remote API
+func checkDockerAPI(ctx context.Context, t CheckTarget) ([]Finding, error) {
+	resp, body, err := checkGet(ctx, t, "/version")
+	if err != nil || resp.StatusCode != http.StatusOK {
+		return nil, err
+	}
+
+	var info struct {
+		Version    string
+		APIVersion string `json:"ApiVersion"`
+		Os         string
+		Arch       string
+	}
+	if err := json.Unmarshal(body, &info); err != nil || info.APIVersion == "" {
+		return nil, nil
+	}
+	return []Finding{{
+		Severity: SeverityCritical,
+		Title:    "Docker remote API exposed without authentication",
+		Detail:   fmt.Sprintf("GET /version returned Docker %s (API %s)", info.Version, info.APIVersion),
+		Data: map[string]string{
+			"version":     info.Version,
+			"api_version": info.APIVersion,
+			"os":          info.Os,
+			"arch":        info.Arch,
+		},
+	}}, nil
+}
+
+var dirListingTitle = regexp.MustCompile(`(?i)<title>\s*((?:Index of|Directory listing for) /[^<]*)</title>`)
+
+// checkDirectoryListing looks for a generated index of the web root
+func checkDirectoryListing(ctx context.Context, t CheckTarget) ([]Finding, error) {
+	resp, body, err := checkGet(ctx, t, "/")
+	if err != nil || resp.StatusCode != http.StatusOK {
+		return nil, err
+	}
+
+	m := dirListingTitle.FindSubmatch(body)
+	if m == nil {
+		return nil, nil
+	}
+	return []Finding{{
+		Severity: SeverityMedium,
+		Title:    "Directory listing enabled",
+		Detail:   strings.TrimSpace(string(m[1])),
+		Data:     map[string]string{"server": resp.Header.Get("Server")},
+	}}, nil
+}
+
+// checkClient does not follow redirects or verify certificates since
+// checks only look at what the target itself returns
+var checkClient = &http.Client{
+	Transport: &http.Transport{
+		TLSClientConfig:   &tls.Config{InsecureSkipVerify: true},
+		DisableKeepAlives: true,
+	},
+	CheckRedirect: func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	},
+}
+
+// checkGet fetches path from the target over https if the port is known
+// to use TLS and returns up to 1MB of the body
+func checkGet(ctx context.Context, t CheckTarget, path string) (*http.Response, []byte, error) {
+	scheme := "http"
+	if checkTLS(t) {
+		scheme = "https"
+	}
+	req, err := http.NewRequestWithContext(ctx, "GET", scheme+"://"+t.Address()+path, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	resp, err := checkClient.Do(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	return resp, body, err
+}
+
+// checkTLS reports if the target port speaks TLS
+func checkTLS(t CheckTarget) bool {
+	if strings.HasPrefix(t.Service, "https") || t.Port == 443 {
+		return true
+	}
+	if t.Result != nil {
+		for _, r := range t.Result.Results {
+			if r.Port == t.Port && r.Version != nil && r.Version.Tunnel == "ssl" {
+				return true
+			}
+		}
+	}
+	return false
+}
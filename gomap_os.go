@@ -0,0 +1,554 @@
+package gomap
+
+import (
+	"bufio"
+	_ "embed"
+	"fmt"
+	"io"
+	"math/rand"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/JustinTimperio/gomap/packet"
+)
+
+//go:embed data/gomap-os-db
+var defaultOSFile string
+
+var (
+	defaultOSDB     []osFingerprint
+	defaultOSDBOnce sync.Once
+)
+
+// osWeights is how much each attribute counts towards the accuracy of a match
+var osWeights = map[string]int{
+	"R":   2,
+	"TTL": 3,
+	"DF":  1,
+	"W":   3,
+	"O":   5,
+	"WS":  2,
+	"CD":  1,
+}
+
+// OSMatch is a guess of the operating system running on a host
+type OSMatch struct {
+	Family   string
+	Version  string
+	Class    string
+	Accuracy int
+}
+
+// String with the family, version range, class and accuracy of a match
+func (m OSMatch) String() string {
+	return fmt.Sprintf("%s %s (%s) %d%%", m.Family, m.Version, m.Class, m.Accuracy)
+}
+
+// osFingerprint is a single entry of the os database
+type osFingerprint struct {
+	family  string
+	version string
+	class   string
+	tests   map[string]map[string][]string
+}
+
+// osObservation holds the attributes of the responses to each probe
+type osObservation map[string]map[string]string
+
+// osProbeResponse is a packet received in response to the probe for test
+type osProbeResponse struct {
+	test string
+	ip   *packet.IPv4
+}
+
+// DetectOS guesses the operating system of hostname from its responses to a
+// battery of crafted TCP, ICMP and UDP probes. openPort should be a TCP port
+// known to be open, or 0 if there is none, and closedPort a TCP port known to
+// be closed. DetectOS MUST be run as root/admin.
+func DetectOS(hostname string, openPort int, closedPort int) ([]OSMatch, error) {
+	dst, err := net.ResolveIPAddr("ip4", hostname)
+	if err != nil {
+		return nil, err
+	}
+	laddr, err := sourceAddress(dst.IP)
+	if err != nil {
+		return nil, err
+	}
+	return detectOS(laddr, hostname, openPort, closedPort, 2*time.Second)
+}
+
+// osPorts picks the open and closed tcp ports used to fingerprint a scanned host
+func osPorts(results []portResult) (int, int) {
+	var open, closed int
+	for _, r := range results {
+		if open == 0 && r.Status == PortOpen {
+			open = r.Port
+		}
+		if closed == 0 && r.Status == PortClosed {
+			closed = r.Port
+		}
+	}
+
+	// A high port is very unlikely to be in use
+	if closed == 0 {
+		closed = random(40000, 60000)
+	}
+	return open, closed
+}
+
+// detectOS probes raddr and scores the responses against the os database
+func detectOS(laddr string, raddr string, openPort int, closedPort int, timeout time.Duration) ([]OSMatch, error) {
+	defaultOSDBOnce.Do(func() {
+		db, err := parseOSDB(strings.NewReader(defaultOSFile))
+		if err != nil {
+			panic("gomap: invalid embedded os database: " + err.Error())
+		}
+		defaultOSDB = db
+	})
+
+	obs, err := probeOS(laddr, raddr, openPort, closedPort, timeout)
+	if err != nil {
+		return nil, err
+	}
+	return matchOS(obs, defaultOSDB), nil
+}
+
+// probeOS sends every probe at once then records the responses until timeout
+func probeOS(laddr string, raddr string, openPort int, closedPort int, timeout time.Duration) (osObservation, error) {
+	dst, err := net.ResolveIPAddr("ip4", raddr)
+	if err != nil {
+		return nil, err
+	}
+	listenAddr, err := net.ResolveIPAddr("ip4", laddr)
+	if err != nil {
+		return nil, err
+	}
+
+	tcpConn, err := net.ListenIP("ip4:tcp", listenAddr)
+	if err != nil {
+		return nil, err
+	}
+	defer tcpConn.Close()
+
+	icmpConn, err := net.ListenIP("ip4:icmp", listenAddr)
+	if err != nil {
+		return nil, err
+	}
+	defer icmpConn.Close()
+
+	// Each probe is sent from its own source port so replies can be matched
+	src := net.ParseIP(laddr)
+	base := uint16(random(10000, 65000))
+	id := uint16(rand.Uint32())
+
+	type tcpProbe struct {
+		test    string
+		port    int
+		flags   packet.TCPFlags
+		options []packet.TCPOption
+	}
+	probes := []tcpProbe{
+		{test: "T5", port: closedPort, flags: packet.TCPSyn, options: []packet.TCPOption{packet.MSSOption(1460)}},
+		{test: "T7", port: closedPort, flags: packet.TCPFin | packet.TCPPsh | packet.TCPUrg},
+	}
+	if openPort != 0 {
+		probes = append(probes,
+			tcpProbe{
+				test:  "T1",
+				port:  openPort,
+				flags: packet.TCPSyn,
+				options: []packet.TCPOption{
+					packet.MSSOption(1460),
+					packet.WindowScaleOption(10),
+					packet.NOPOption(),
+					packet.NOPOption(),
+					packet.TimestampsOption(0xffffffff, 0),
+					packet.SACKPermittedOption(),
+				},
+			},
+			tcpProbe{test: "T4", port: openPort, flags: packet.TCPAck},
+		)
+	}
+
+	tests := map[uint16]string{}
+	deadline := time.Now().Add(timeout)
+	responses := make(chan osProbeResponse, len(probes)+2)
+
+	for i, p := range probes {
+		tests[base+uint16(i)] = p.test
+	}
+	udpPort := base + uint16(len(probes))
+
+	// done stops the receivers once the responses are no longer collected
+	done := make(chan struct{})
+	defer close(done)
+
+	go recvOSTCP(tcpConn, dst.IP, tests, deadline, responses, done)
+	go recvOSICMP(icmpConn, dst.IP, udpPort, id, deadline, responses, done)
+
+	for i, p := range probes {
+		tcp := packet.TCP{
+			SrcPort: base + uint16(i),
+			DstPort: uint16(p.port),
+			Seq:     rand.Uint32(),
+			Flags:   p.flags,
+			Window:  1024,
+			Options: p.options,
+		}
+		if p.flags&packet.TCPAck != 0 {
+			tcp.Ack = rand.Uint32()
+		}
+		data, err := tcp.Marshal(src, dst.IP)
+		if err != nil {
+			return nil, err
+		}
+		if err := sendRaw(src, dst.IP, "tcp", data); err != nil {
+			return nil, err
+		}
+	}
+
+	udp := packet.UDP{
+		SrcPort: udpPort,
+		DstPort: uint16(random(40000, 60000)),
+		Payload: []byte(strings.Repeat("C", 300)),
+	}
+	data, err := udp.Marshal(src, dst.IP)
+	if err != nil {
+		return nil, err
+	}
+	if err := sendRaw(src, dst.IP, "udp", data); err != nil {
+		return nil, err
+	}
+
+	echo := packet.ICMP{
+		Type:    packet.ICMPEchoRequest,
+		Code:    9,
+		Rest:    uint32(id)<<16 | 295,
+		Payload: make([]byte, 120),
+	}
+	data, err = echo.Marshal()
+	if err != nil {
+		return nil, err
+	}
+	if err := sendRaw(src, dst.IP, "icmp", data); err != nil {
+		return nil, err
+	}
+
+	obs := osObservation{"IE": {"R": "N"}, "U1": {"R": "N"}}
+	for _, p := range probes {
+		obs[p.test] = map[string]string{"R": "N"}
+	}
+
+	timer := time.NewTimer(time.Until(deadline))
+	defer timer.Stop()
+	for received := 0; received < len(obs); {
+		select {
+		case r := <-responses:
+			if obs[r.test]["R"] == "N" {
+				obs[r.test] = responseAttributes(r.ip)
+				received++
+			}
+		case <-timer.C:
+			return obs, nil
+		}
+	}
+	return obs, nil
+}
+
+// responseAttributes extracts the fingerprint attributes of a response
+func responseAttributes(ip *packet.IPv4) map[string]string {
+	attrs := map[string]string{
+		"R":   "Y",
+		"TTL": strconv.Itoa(initialTTL(ip.TTL)),
+		"DF":  "N",
+	}
+	if ip.Flags&packet.IPv4DontFragment != 0 {
+		attrs["DF"] = "Y"
+	}
+
+	switch ip.Protocol {
+	case packet.ProtocolTCP:
+		tcp, err := packet.ParseTCP(ip.Payload)
+		if err != nil {
+			break
+		}
+		attrs["W"] = strconv.Itoa(int(tcp.Window))
+
+		var order []byte
+		for _, o := range tcp.Options {
+			order = append(order, optionLetter(o.Kind))
+			if o.Kind == packet.TCPOptionWindowScale && len(o.Data) == 1 {
+				attrs["WS"] = strconv.Itoa(int(o.Data[0]))
+			}
+		}
+		attrs["O"] = string(order)
+	case packet.ProtocolICMP:
+		icmp, err := packet.ParseICMP(ip.Payload)
+		if err != nil || icmp.Type != packet.ICMPEchoReply {
+			break
+		}
+		switch icmp.Code {
+		case 0:
+			attrs["CD"] = "Z"
+		case 9:
+			attrs["CD"] = "S"
+		default:
+			attrs["CD"] = "O"
+		}
+	}
+	return attrs
+}
+
+// optionLetter returns the letter used for a tcp option kind in the O attribute
+func optionLetter(kind uint8) byte {
+	switch kind {
+	case packet.TCPOptionEnd:
+		return 'E'
+	case packet.TCPOptionNOP:
+		return 'N'
+	case packet.TCPOptionMSS:
+		return 'M'
+	case packet.TCPOptionWindowScale:
+		return 'W'
+	case packet.TCPOptionSACKPermitted:
+		return 'S'
+	case packet.TCPOptionTimestamps:
+		return 'T'
+	}
+	return 'X'
+}
+
+// initialTTL guesses the ttl a packet was sent with from the ttl it arrived with
+func initialTTL(ttl uint8) int {
+	for _, t := range []int{32, 64, 128} {
+		if int(ttl) <= t {
+			return t
+		}
+	}
+	return 255
+}
+
+// recvOSTCP collects the replies from dst to the tcp probes
+func recvOSTCP(conn *net.IPConn, dst net.IP, tests map[uint16]string, deadline time.Time, responses chan<- osProbeResponse, done <-chan struct{}) {
+	conn.SetReadDeadline(deadline)
+
+	buff := make([]byte, 1500)
+	for {
+		// ReadMsgIP keeps the ip header which holds the ttl and df bit
+		n, _, _, addr, err := conn.ReadMsgIP(buff, nil)
+		if err != nil {
+			if ne, ok := err.(net.Error); ok && ne.Timeout() {
+				return
+			}
+			select {
+			case <-done:
+				return
+			default:
+				continue
+			}
+		}
+		if !addr.IP.Equal(dst) {
+			continue
+		}
+
+		ip, err := packet.ParseIPv4(append([]byte(nil), buff[:n]...))
+		if err != nil {
+			continue
+		}
+		tcp, err := packet.ParseTCP(ip.Payload)
+		if err != nil {
+			continue
+		}
+		if test, ok := tests[tcp.DstPort]; ok {
+			if !sendOSResponse(responses, done, osProbeResponse{test: test, ip: ip}) {
+				return
+			}
+		}
+	}
+}
+
+// recvOSICMP collects the echo reply and the port unreachable error caused by the udp probe
+func recvOSICMP(conn *net.IPConn, dst net.IP, udpPort uint16, id uint16, deadline time.Time, responses chan<- osProbeResponse, done <-chan struct{}) {
+	conn.SetReadDeadline(deadline)
+
+	buff := make([]byte, 1500)
+	for {
+		n, _, _, addr, err := conn.ReadMsgIP(buff, nil)
+		if err != nil {
+			if ne, ok := err.(net.Error); ok && ne.Timeout() {
+				return
+			}
+			select {
+			case <-done:
+				return
+			default:
+				continue
+			}
+		}
+		if !addr.IP.Equal(dst) {
+			continue
+		}
+
+		ip, err := packet.ParseIPv4(append([]byte(nil), buff[:n]...))
+		if err != nil {
+			continue
+		}
+		icmp, err := packet.ParseICMP(ip.Payload)
+		if err != nil {
+			continue
+		}
+
+		switch icmp.Type {
+		case packet.ICMPEchoReply:
+			if icmp.ID() == id {
+				if !sendOSResponse(responses, done, osProbeResponse{test: "IE", ip: ip}) {
+					return
+				}
+			}
+		case packet.ICMPDestinationUnreachable:
+			quoted, err := packet.ParseIPv4(icmp.Payload)
+			if err != nil || quoted.Protocol != packet.ProtocolUDP {
+				continue
+			}
+			udp, err := packet.ParseUDP(quoted.Payload)
+			if err == nil && udp.SrcPort == udpPort {
+				if !sendOSResponse(responses, done, osProbeResponse{test: "U1", ip: ip}) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// sendOSResponse passes r to the collector, it returns false once done is closed
+func sendOSResponse(responses chan<- osProbeResponse, done <-chan struct{}, r osProbeResponse) bool {
+	select {
+	case responses <- r:
+		return true
+	case <-done:
+		return false
+	}
+}
+
+// matchOS scores obs against every fingerprint and returns the best matches
+func matchOS(obs osObservation, db []osFingerprint) []OSMatch {
+	var matches []OSMatch
+	for _, fp := range db {
+		var points, possible int
+		for test, attrs := range fp.tests {
+			got, ok := obs[test]
+			if !ok {
+				// The probe was not sent, usually because there is no open port
+				continue
+			}
+
+			for attr, values := range attrs {
+				// Only the response itself can be compared if there was none
+				if got["R"] == "N" && attr != "R" {
+					continue
+				}
+				possible += osWeights[attr]
+				if containsString(values, got[attr]) {
+					points += osWeights[attr]
+				}
+			}
+
+			// Fingerprints expect a response unless they say otherwise
+			if _, ok := attrs["R"]; !ok {
+				possible += osWeights["R"]
+				if got["R"] == "Y" {
+					points += osWeights["R"]
+				}
+			}
+		}
+
+		if possible == 0 {
+			continue
+		}
+		matches = append(matches, OSMatch{
+			Family:   fp.family,
+			Version:  fp.version,
+			Class:    fp.class,
+			Accuracy: points * 100 / possible,
+		})
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].Accuracy > matches[j].Accuracy
+	})
+
+	// Only keep the few guesses that are worth reporting
+	var best []OSMatch
+	for _, m := range matches {
+		if len(best) == 3 || m.Accuracy < 50 {
+			break
+		}
+		best = append(best, m)
+	}
+	return best
+}
+
+// parseOSDB parses the os fingerprint database format described in data/gomap-os-db
+func parseOSDB(r io.Reader) ([]osFingerprint, error) {
+	var db []osFingerprint
+	scanner := bufio.NewScanner(r)
+
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		if strings.HasPrefix(text, "Fingerprint ") {
+			parts := strings.Split(strings.TrimPrefix(text, "Fingerprint "), "|")
+			if len(parts) != 3 {
+				return nil, fmt.Errorf("os db: line %d: expected family | version | class", line)
+			}
+			db = append(db, osFingerprint{
+				family:  strings.TrimSpace(parts[0]),
+				version: strings.TrimSpace(parts[1]),
+				class:   strings.TrimSpace(parts[2]),
+				tests:   make(map[string]map[string][]string),
+			})
+			continue
+		}
+
+		if len(db) == 0 {
+			return nil, fmt.Errorf("os db: line %d: test before first fingerprint", line)
+		}
+		fields := strings.Fields(text)
+		attrs := make(map[string][]string)
+		for _, f := range fields[1:] {
+			kv := strings.SplitN(f, "=", 2)
+			if len(kv) != 2 {
+				return nil, fmt.Errorf("os db: line %d: invalid attribute %q", line, f)
+			}
+			if _, ok := osWeights[kv[0]]; !ok {
+				return nil, fmt.Errorf("os db: line %d: unknown attribute %q", line, kv[0])
+			}
+			attrs[kv[0]] = strings.Split(kv[1], "|")
+		}
+		db[len(db)-1].tests[fields[0]] = attrs
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return db, nil
+}
+
+// containsString reports if s is in list
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
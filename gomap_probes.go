@@ -0,0 +1,819 @@
+package gomap
+
+import (
+	"bufio"
+	"crypto/tls"
+	_ "embed"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+//go:embed data/gomap-service-probes
+var defaultProbesFile string
+
+var (
+	defaultProbes     *ProbeDB
+	defaultProbesOnce sync.Once
+)
+
+// ServiceVersion is what version detection found out about a service
+type ServiceVersion struct {
+	Service    string
+	Product    string   `json:",omitempty"`
+	Version    string   `json:",omitempty"`
+	Info       string   `json:",omitempty"`
+	Hostname   string   `json:",omitempty"`
+	OS         string   `json:",omitempty"`
+	DeviceType string   `json:",omitempty"`
+	CPE        []string `json:",omitempty"`
+	// Tunnel is "ssl" when the service was found inside TLS
+	Tunnel string `json:",omitempty"`
+	// Probe is the name of the probe whose response matched
+	Probe string
+	// Soft is set when only a softmatch named the service
+	Soft bool `json:",omitempty"`
+}
+
+// String with the service, product, version and extra information
+func (v *ServiceVersion) String() string {
+	service := v.Service
+	if v.Tunnel != "" {
+		service = v.Tunnel + "/" + service
+	}
+	if v.Soft {
+		service += "?"
+	}
+
+	s := strings.Join(strings.Fields(service+" "+v.Product+" "+v.Version), " ")
+	if v.Info != "" {
+		s += " (" + v.Info + ")"
+	}
+	if v.Hostname != "" {
+		s += "; Host: " + v.Hostname
+	}
+	if v.OS != "" {
+		s += "; OS: " + v.OS
+	}
+	if v.DeviceType != "" {
+		s += "; Device: " + v.DeviceType
+	}
+	return s
+}
+
+// ServiceProbe is a payload sent to a port and the patterns its
+// responses are matched against
+type ServiceProbe struct {
+	// Protocol is "tcp" or "udp"
+	Protocol string
+	Name     string
+	Payload  []byte
+	// Rarity from 1 to 9 ranks how rarely the probe is useful. Probes
+	// without a rarity are always sent.
+	Rarity int
+	// TotalWait is how long to wait for a response, 5 seconds if unset
+	TotalWait time.Duration
+	// Fallback names probes whose matches are also tried on the response
+	Fallback []string
+	Matches  []ServiceMatch
+
+	ports    portRanges
+	sslports portRanges
+}
+
+// ServiceMatch identifies a service from a probe response. The version
+// fields are templates that may refer to the submatches of Pattern.
+type ServiceMatch struct {
+	Service string
+	// Soft matches only name the service and let detection continue
+	Soft    bool
+	Pattern *regexp.Regexp
+
+	Product    string
+	Version    string
+	Info       string
+	Hostname   string
+	OS         string
+	DeviceType string
+	CPE        []string
+}
+
+// portRanges is a list of inclusive port ranges
+type portRanges [][2]int
+
+// contains reports if port is in one of the ranges
+func (p portRanges) contains(port int) bool {
+	for _, r := range p {
+		if port >= r[0] && port <= r[1] {
+			return true
+		}
+	}
+	return false
+}
+
+// ProbeDB holds the probes used by version detection. It is loaded from
+// files in the nmap-service-probes format.
+type ProbeDB struct {
+	mu      sync.RWMutex
+	probes  []*ServiceProbe
+	exclude map[string]portRanges
+}
+
+// NewProbeDB returns an empty probe database
+func NewProbeDB() *ProbeDB {
+	return &ProbeDB{exclude: make(map[string]portRanges)}
+}
+
+// DefaultProbes returns the probe database embedded in gomap. It is
+// shared and must not be changed, Merge it into a NewProbeDB to extend it.
+func DefaultProbes() *ProbeDB {
+	defaultProbesOnce.Do(func() {
+		db, err := ParseProbes(strings.NewReader(defaultProbesFile))
+		if err != nil {
+			panic("gomap: invalid embedded probe database: " + err.Error())
+		}
+		defaultProbes = db
+	})
+	return defaultProbes
+}
+
+// LoadProbes reads a probe database from an nmap-service-probes formatted file
+func LoadProbes(path string) (*ProbeDB, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return ParseProbes(f)
+}
+
+// ParseProbes parses a probe database in the nmap-service-probes format
+// described in data/gomap-service-probes. Patterns using features the
+// regexp package lacks, such as backreferences and lookarounds, are
+// skipped so nmap's own file can be loaded.
+func ParseProbes(r io.Reader) (*ProbeDB, error) {
+	db := NewProbeDB()
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	var probe *ServiceProbe
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		directive, rest := text, ""
+		if i := strings.IndexAny(text, " \t"); i >= 0 {
+			directive, rest = text[:i], strings.TrimSpace(text[i+1:])
+		}
+
+		if directive == "Probe" {
+			p, err := parseProbeLine(rest)
+			if err != nil {
+				return nil, fmt.Errorf("probes: line %d: %v", line, err)
+			}
+			probe = p
+			db.probes = append(db.probes, p)
+			continue
+		}
+		if directive == "Exclude" {
+			if err := db.parseExclude(rest); err != nil {
+				return nil, fmt.Errorf("probes: line %d: %v", line, err)
+			}
+			continue
+		}
+		if probe == nil {
+			return nil, fmt.Errorf("probes: line %d: %s before first probe", line, directive)
+		}
+
+		var err error
+		switch directive {
+		case "match", "softmatch":
+			var m *ServiceMatch
+			m, err = parseMatchLine(rest, directive == "softmatch")
+			if m != nil {
+				probe.Matches = append(probe.Matches, *m)
+			}
+		case "ports":
+			probe.ports, err = parsePortRanges(rest)
+		case "sslports":
+			probe.sslports, err = parsePortRanges(rest)
+		case "rarity":
+			probe.Rarity, err = strconv.Atoi(rest)
+			if err == nil && (probe.Rarity < 1 || probe.Rarity > 9) {
+				err = fmt.Errorf("rarity %d out of range", probe.Rarity)
+			}
+		case "totalwaitms":
+			var ms int
+			ms, err = strconv.Atoi(rest)
+			probe.TotalWait = time.Duration(ms) * time.Millisecond
+		case "tcpwrappedms":
+			// Connections closed this quickly are reported by nmap as
+			// tcpwrapped, gomap simply reports no match
+		case "fallback":
+			for _, name := range strings.Split(rest, ",") {
+				if name = strings.TrimSpace(name); name != "" {
+					probe.Fallback = append(probe.Fallback, name)
+				}
+			}
+		default:
+			err = fmt.Errorf("unknown directive %q", directive)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("probes: line %d: %v", line, err)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return db, nil
+}
+
+// parseProbeLine parses "<protocol> <name> q|<payload>| [no-payload]"
+func parseProbeLine(s string) (*ServiceProbe, error) {
+	fields := strings.Fields(s)
+	if len(fields) < 3 {
+		return nil, errors.New("expected protocol, name and payload")
+	}
+	proto := strings.ToLower(fields[0])
+	if proto != "tcp" && proto != "udp" {
+		return nil, fmt.Errorf("invalid protocol %q", fields[0])
+	}
+
+	rest := strings.TrimSpace(strings.TrimSpace(s[len(fields[0]):])[len(fields[1]):])
+	if !strings.HasPrefix(rest, "q") {
+		return nil, errors.New("payload must start with q")
+	}
+	payload, _, err := splitDelimited(rest[1:])
+	if err != nil {
+		return nil, err
+	}
+	data, err := unescapePayload(payload)
+	if err != nil {
+		return nil, err
+	}
+	return &ServiceProbe{Protocol: proto, Name: fields[1], Payload: data}, nil
+}
+
+// parseMatchLine parses "<service> m|<regex>|[flags] [<field>/<value>/ ...]".
+// It returns nil without an error for patterns the regexp package cannot compile.
+func parseMatchLine(s string, soft bool) (*ServiceMatch, error) {
+	i := strings.IndexAny(s, " \t")
+	if i < 0 {
+		return nil, errors.New("expected service and pattern")
+	}
+	m := &ServiceMatch{Service: s[:i], Soft: soft}
+
+	rest := strings.TrimSpace(s[i+1:])
+	if !strings.HasPrefix(rest, "m") {
+		return nil, errors.New("pattern must start with m")
+	}
+	pattern, rest, err := splitDelimited(rest[1:])
+	if err != nil {
+		return nil, err
+	}
+
+	var flags string
+	for len(rest) > 0 && rest[0] != ' ' && rest[0] != '\t' {
+		switch rest[0] {
+		case 'i', 's':
+			flags += rest[:1]
+		default:
+			return nil, fmt.Errorf("unknown pattern flag %q", rest[:1])
+		}
+		rest = rest[1:]
+	}
+	if flags != "" {
+		pattern = "(?" + flags + ")" + pattern
+	}
+	if m.Pattern, err = regexp.Compile(pattern); err != nil {
+		return nil, nil
+	}
+
+	for rest = strings.TrimSpace(rest); rest != ""; rest = strings.TrimSpace(rest) {
+		if strings.HasPrefix(rest, "cpe:") {
+			var cpe string
+			if cpe, rest, err = splitDelimited(rest[4:]); err != nil {
+				return nil, err
+			}
+			// The trailing a marks an application and is implied by the cpe itself
+			rest = strings.TrimPrefix(rest, "a")
+			m.CPE = append(m.CPE, "cpe:/"+cpe)
+			continue
+		}
+
+		field := rest[0]
+		var value string
+		if value, rest, err = splitDelimited(rest[1:]); err != nil {
+			return nil, err
+		}
+		switch field {
+		case 'p':
+			m.Product = value
+		case 'v':
+			m.Version = value
+		case 'i':
+			m.Info = value
+		case 'h':
+			m.Hostname = value
+		case 'o':
+			m.OS = value
+		case 'd':
+			m.DeviceType = value
+		default:
+			return nil, fmt.Errorf("unknown version field %q", string(field))
+		}
+	}
+	return m, nil
+}
+
+// splitDelimited splits a value wrapped in the delimiter that starts s
+// from the text that follows it
+func splitDelimited(s string) (string, string, error) {
+	if s == "" {
+		return "", "", errors.New("missing delimiter")
+	}
+	end := strings.IndexByte(s[1:], s[0])
+	if end < 0 {
+		return "", "", fmt.Errorf("unterminated %q delimited value", s[:1])
+	}
+	return s[1 : end+1], s[end+2:], nil
+}
+
+// unescapePayload decodes the C style escapes of a probe payload
+func unescapePayload(s string) ([]byte, error) {
+	var b []byte
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' {
+			b = append(b, s[i])
+			continue
+		}
+		if i++; i == len(s) {
+			return nil, errors.New("payload ends with a backslash")
+		}
+		switch s[i] {
+		case '0':
+			b = append(b, 0)
+		case 'a':
+			b = append(b, '\a')
+		case 'b':
+			b = append(b, '\b')
+		case 'f':
+			b = append(b, '\f')
+		case 'n':
+			b = append(b, '\n')
+		case 'r':
+			b = append(b, '\r')
+		case 't':
+			b = append(b, '\t')
+		case 'v':
+			b = append(b, '\v')
+		case 'x':
+			if i+2 >= len(s) {
+				return nil, errors.New("truncated \\x escape in payload")
+			}
+			v, err := strconv.ParseUint(s[i+1:i+3], 16, 8)
+			if err != nil {
+				return nil, fmt.Errorf("invalid \\x escape %q in payload", s[i-1:i+3])
+			}
+			b = append(b, byte(v))
+			i += 2
+		default:
+			b = append(b, s[i])
+		}
+	}
+	return b, nil
+}
+
+// parsePortRanges parses a list such as "80,443,8000-8010"
+func parsePortRanges(s string) (portRanges, error) {
+	var ranges portRanges
+	for _, item := range strings.Split(s, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		bounds := strings.SplitN(item, "-", 2)
+		lo, err := strconv.Atoi(bounds[0])
+		hi := lo
+		if err == nil && len(bounds) == 2 {
+			hi, err = strconv.Atoi(bounds[1])
+		}
+		if err != nil || lo < 0 || hi > 65535 || lo > hi {
+			return nil, fmt.Errorf("invalid port range %q", item)
+		}
+		ranges = append(ranges, [2]int{lo, hi})
+	}
+	return ranges, nil
+}
+
+// parseExclude parses a port list where T: and U: switch the protocol
+// of the ports that follow. Ports without a protocol apply to both.
+func (db *ProbeDB) parseExclude(s string) error {
+	protos := []string{"tcp", "udp"}
+	for _, item := range strings.Split(s, ",") {
+		item = strings.TrimSpace(item)
+		switch {
+		case strings.HasPrefix(item, "T:"):
+			protos, item = []string{"tcp"}, item[2:]
+		case strings.HasPrefix(item, "U:"):
+			protos, item = []string{"udp"}, item[2:]
+		}
+		ranges, err := parsePortRanges(item)
+		if err != nil {
+			return err
+		}
+		for _, p := range protos {
+			db.exclude[p] = append(db.exclude[p], ranges...)
+		}
+	}
+	return nil
+}
+
+// Merge copies the probes and excluded ports of other into db. A probe
+// with the same protocol and name as one already in db extends it: its
+// matches are tried first, its ports are added and its payload, rarity,
+// wait and fallbacks replace the old ones when set. This lets a file add
+// signatures to the default probes by repeating their Probe line.
+func (db *ProbeDB) Merge(other *ProbeDB) {
+	other.mu.RLock()
+	defer other.mu.RUnlock()
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	for proto, ranges := range other.exclude {
+		db.exclude[proto] = append(db.exclude[proto], ranges...)
+	}
+
+	for _, p := range other.probes {
+		existing := db.find(p.Protocol, p.Name)
+		if existing == nil {
+			cp := *p
+			cp.Payload = append([]byte{}, p.Payload...)
+			cp.Fallback = append([]string{}, p.Fallback...)
+			cp.Matches = append([]ServiceMatch{}, p.Matches...)
+			cp.ports = append(portRanges{}, p.ports...)
+			cp.sslports = append(portRanges{}, p.sslports...)
+			db.probes = append(db.probes, &cp)
+			continue
+		}
+
+		existing.Payload = append([]byte{}, p.Payload...)
+		existing.Matches = append(append([]ServiceMatch{}, p.Matches...), existing.Matches...)
+		existing.ports = append(existing.ports, p.ports...)
+		existing.sslports = append(existing.sslports, p.sslports...)
+		if p.Rarity != 0 {
+			existing.Rarity = p.Rarity
+		}
+		if p.TotalWait != 0 {
+			existing.TotalWait = p.TotalWait
+		}
+		if len(p.Fallback) > 0 {
+			existing.Fallback = append([]string{}, p.Fallback...)
+		}
+	}
+}
+
+// Probes returns the probes in the database in the order they are sent
+func (db *ProbeDB) Probes() []*ServiceProbe {
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+	return append([]*ServiceProbe{}, db.probes...)
+}
+
+// find returns the probe named name on proto, db.mu must be held
+func (db *ProbeDB) find(proto, name string) *ServiceProbe {
+	for _, p := range db.probes {
+		if p.Protocol == proto && p.Name == name {
+			return p
+		}
+	}
+	return nil
+}
+
+// DetectVersion identifies the service listening on port of hostname and
+// its version using the default probes. proto is "tcp" or "udp".
+func DetectVersion(hostname string, port int, proto string) (*ServiceVersion, error) {
+	return DefaultProbes().Detect(hostname, port, proto, 7)
+}
+
+// Detect identifies the service listening on port of hostname and its
+// version using the probes in db with a rarity up to intensity (1-9)
+func (db *ProbeDB) Detect(hostname string, port int, proto string, intensity int) (*ServiceVersion, error) {
+	return db.detect(hostname, port, proto, intensity, 5*time.Second)
+}
+
+// detect sends probes until one identifies the service, waiting at most
+// timeout for each response. Services found to be TLS are probed again
+// through a TLS connection.
+func (db *ProbeDB) detect(hostname string, port int, proto string, intensity int, timeout time.Duration) (*ServiceVersion, error) {
+	proto = serviceProto(proto)
+	db.mu.RLock()
+	excluded := db.exclude[proto].contains(port)
+	db.mu.RUnlock()
+	if excluded {
+		return nil, fmt.Errorf("version detection: %d/%s is excluded", port, proto)
+	}
+
+	address := net.JoinHostPort(hostname, strconv.Itoa(port))
+	v, err := db.runProbes(address, port, proto, intensity, false, timeout)
+	if v != nil && v.Service == "ssl" && proto == "tcp" {
+		if inner, _ := db.runProbes(address, port, proto, intensity, true, timeout); inner != nil {
+			inner.Tunnel = "ssl"
+			return inner, nil
+		}
+	}
+	return v, err
+}
+
+// runProbes sends each probe suited to the port in turn and returns the
+// first match, or the first softmatch if nothing matched
+func (db *ProbeDB) runProbes(address string, port int, proto string, intensity int, useTLS bool, timeout time.Duration) (*ServiceVersion, error) {
+	var soft *ServiceVersion
+	err := errors.New("version detection: no match")
+	for _, p := range db.probeOrder(proto, port, intensity, useTLS) {
+		// Once the service is known only probes that can identify it are worth sending
+		if soft != nil && !p.identifies(soft.Service) {
+			continue
+		}
+
+		v, hard, perr := db.sendProbe(p, address, proto, useTLS, timeout)
+		if perr != nil {
+			err = perr
+			continue
+		}
+		if hard {
+			return v, nil
+		}
+		if v != nil && soft == nil {
+			soft = v
+		}
+	}
+	if soft != nil {
+		return soft, nil
+	}
+	return nil, err
+}
+
+// probeOrder returns the probes to send to port: NULL first for tcp,
+// then those listing the port and then the rest within intensity
+func (db *ProbeDB) probeOrder(proto string, port int, intensity int, useTLS bool) []*ServiceProbe {
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+
+	var listed, rest []*ServiceProbe
+	for _, p := range db.probes {
+		if p.Protocol != proto || (proto == "udp" && len(p.Payload) == 0) {
+			continue
+		}
+		ports := p.ports
+		if useTLS {
+			ports = p.sslports
+		}
+		switch {
+		case p.Name == "NULL":
+			listed = append([]*ServiceProbe{p}, listed...)
+		case ports.contains(port):
+			listed = append(listed, p)
+		case p.Rarity <= intensity:
+			rest = append(rest, p)
+		}
+	}
+	return append(listed, rest...)
+}
+
+// identifies reports if the probe has a match for service
+func (p *ServiceProbe) identifies(service string) bool {
+	for _, m := range p.Matches {
+		if m.Service == service {
+			return true
+		}
+	}
+	return false
+}
+
+// sendProbe sends the payload of p on a new connection and matches what
+// comes back, stopping early once the response so far matches
+func (db *ProbeDB) sendProbe(p *ServiceProbe, address string, proto string, useTLS bool, timeout time.Duration) (*ServiceVersion, bool, error) {
+	wait := p.TotalWait
+	if wait <= 0 {
+		wait = 5 * time.Second
+	}
+	if wait > timeout {
+		wait = timeout
+	}
+
+	conn, err := net.DialTimeout(proto, address, timeout)
+	if err != nil {
+		return nil, false, err
+	}
+	defer conn.Close()
+
+	conn.SetDeadline(time.Now().Add(timeout))
+	if useTLS {
+		tconn := tls.Client(conn, &tls.Config{InsecureSkipVerify: true})
+		if err := tconn.Handshake(); err != nil {
+			return nil, false, err
+		}
+		conn = tconn
+	}
+
+	if len(p.Payload) > 0 {
+		if _, err := conn.Write(p.Payload); err != nil {
+			return nil, false, err
+		}
+	}
+
+	conn.SetReadDeadline(time.Now().Add(wait))
+	var resp []byte
+	var soft *ServiceVersion
+	buff := make([]byte, 4096)
+	for len(resp) < 64*1024 {
+		n, err := conn.Read(buff)
+		if n > 0 {
+			resp = append(resp, buff[:n]...)
+			v, hard := db.match(p, resp)
+			if hard {
+				return v, true, nil
+			}
+			soft = v
+		}
+		if err != nil {
+			break
+		}
+	}
+	return soft, false, nil
+}
+
+// match tries the matches of p, its fallbacks and for tcp the NULL probe
+// against resp. It returns the first match or else the first softmatch
+// and whether it was a hard match.
+func (db *ProbeDB) match(p *ServiceProbe, resp []byte) (*ServiceVersion, bool) {
+	db.mu.RLock()
+	candidates := []*ServiceProbe{p}
+	for _, name := range p.Fallback {
+		if f := db.find(p.Protocol, name); f != nil {
+			candidates = append(candidates, f)
+		}
+	}
+	if p.Protocol == "tcp" && p.Name != "NULL" {
+		if null := db.find("tcp", "NULL"); null != nil {
+			candidates = append(candidates, null)
+		}
+	}
+	db.mu.RUnlock()
+
+	// Each byte becomes one character so patterns such as \xff match
+	// raw bytes instead of utf-8 sequences
+	text := latin1(resp)
+	var soft *ServiceVersion
+	for _, c := range candidates {
+		for _, m := range c.Matches {
+			groups := m.Pattern.FindStringSubmatch(text)
+			if groups == nil {
+				continue
+			}
+			if m.Soft {
+				if soft == nil {
+					soft = &ServiceVersion{Service: m.Service, Probe: p.Name, Soft: true}
+				}
+				continue
+			}
+			return m.version(p.Name, groups), true
+		}
+	}
+	return soft, false
+}
+
+// version fills in the templates of m with the submatches in groups
+func (m *ServiceMatch) version(probe string, groups []string) *ServiceVersion {
+	v := &ServiceVersion{
+		Service:    m.Service,
+		Product:    expandTemplate(m.Product, groups),
+		Version:    expandTemplate(m.Version, groups),
+		Info:       expandTemplate(m.Info, groups),
+		Hostname:   expandTemplate(m.Hostname, groups),
+		OS:         expandTemplate(m.OS, groups),
+		DeviceType: expandTemplate(m.DeviceType, groups),
+		Probe:      probe,
+	}
+	for _, c := range m.CPE {
+		v.CPE = append(v.CPE, expandTemplate(c, groups))
+	}
+	return v
+}
+
+var templateFunc = regexp.MustCompile(`^\$(P|SUBST|I)\((\d)(?:,"([^"]*)")?(?:,"([^"]*)")?\)`)
+
+// expandTemplate replaces $1-$9, $P(n), $SUBST(n,"from","to") and
+// $I(n,">") in a version field with the submatches in groups
+func expandTemplate(tmpl string, groups []string) string {
+	if !strings.Contains(tmpl, "$") {
+		return tmpl
+	}
+
+	group := func(n string) string {
+		i, _ := strconv.Atoi(n)
+		if i < len(groups) {
+			return unlatin1(groups[i])
+		}
+		return ""
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(tmpl); i++ {
+		if tmpl[i] != '$' {
+			b.WriteByte(tmpl[i])
+			continue
+		}
+		if i+1 < len(tmpl) && tmpl[i+1] >= '1' && tmpl[i+1] <= '9' {
+			b.WriteString(group(tmpl[i+1 : i+2]))
+			i++
+			continue
+		}
+
+		f := templateFunc.FindStringSubmatch(tmpl[i:])
+		if f == nil {
+			b.WriteByte('$')
+			continue
+		}
+		value := group(f[2])
+		switch f[1] {
+		case "P":
+			for _, r := range value {
+				if r >= 0x20 && r < 0x7f {
+					b.WriteRune(r)
+				}
+			}
+		case "SUBST":
+			b.WriteString(strings.ReplaceAll(value, f[3], f[4]))
+		case "I":
+			var n uint64
+			for j := range value {
+				if f[3] == "<" {
+					n |= uint64(value[j]) << (8 * uint(j))
+				} else {
+					n = n<<8 | uint64(value[j])
+				}
+			}
+			b.WriteString(strconv.FormatUint(n, 10))
+		}
+		i += len(f[0]) - 1
+	}
+	return strings.TrimSpace(b.String())
+}
+
+// latin1 maps each byte of b to the character with the same value
+func latin1(b []byte) string {
+	r := make([]rune, len(b))
+	for i, c := range b {
+		r[i] = rune(c)
+	}
+	return string(r)
+}
+
+// unlatin1 reverses latin1
+func unlatin1(s string) string {
+	b := make([]byte, 0, len(s))
+	for _, r := range s {
+		b = append(b, byte(r))
+	}
+	return string(b)
+}
+
+// detectVersions runs version detection on the open ports of target in
+// parallel and names each port after the service found
+func detectVersions(target string, results []portResult, opts *ScanOptions) {
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, 10)
+	for i := range results {
+		if addressState(results[i]) != PortOpen {
+			continue
+		}
+		wg.Add(1)
+		go func(r *portResult) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			v, err := opts.Probes.detect(target, r.Port, opts.Proto, opts.VersionIntensity, 5*time.Second)
+			if err == nil {
+				r.Version = v
+				r.Service = v.Service
+			}
+		}(&results[i])
+	}
+	wg.Wait()
+}
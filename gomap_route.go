@@ -0,0 +1,157 @@
+package gomap
+
+import (
+	"fmt"
+	"net"
+)
+
+// Route is how packets to a target leave this host
+type Route struct {
+	Interface string
+	Source    net.IP
+	// Gateway is the next hop, nil if the target is directly connected
+	Gateway net.IP
+}
+
+// routeEntry is a single ipv4 route from the routing table
+type routeEntry struct {
+	iface   string
+	dst     net.IPNet
+	gateway net.IP
+	metric  int
+}
+
+// RouteTo looks up the egress interface, source address and next hop used to reach dst
+func RouteTo(dst net.IP) (*Route, error) {
+	dst4 := dst.To4()
+	if dst4 == nil {
+		return nil, fmt.Errorf("route: %s is not an ipv4 address", dst)
+	}
+
+	ifaces, err := Interfaces()
+	if err != nil {
+		return nil, err
+	}
+
+	// Local addresses never appear in the main routing table
+	for _, iface := range ifaces {
+		for _, a := range iface.Addrs {
+			if a.IP.Equal(dst4) || (dst4.IsLoopback() && iface.Flags&net.FlagLoopback != 0 && a.IP.To4() != nil) {
+				return &Route{Interface: iface.Name, Source: a.IP.To4()}, nil
+			}
+		}
+	}
+
+	routes, err := readRoutes()
+	if err != nil {
+		return routeFromDial(dst4, ifaces)
+	}
+
+	// Longest prefix wins with the lowest metric breaking ties
+	var best *routeEntry
+	for i, r := range routes {
+		if !r.dst.Contains(dst4) {
+			continue
+		}
+		if best == nil {
+			best = &routes[i]
+			continue
+		}
+		ones, _ := r.dst.Mask.Size()
+		bestOnes, _ := best.dst.Mask.Size()
+		if ones > bestOnes || (ones == bestOnes && r.metric < best.metric) {
+			best = &routes[i]
+		}
+	}
+	if best == nil {
+		return nil, fmt.Errorf("route: no route to %s", dst4)
+	}
+
+	route := &Route{Interface: best.iface, Gateway: best.gateway}
+	hop := dst4
+	if best.gateway != nil {
+		hop = best.gateway
+	}
+
+	// Prefer the interface address on the same subnet as the next hop
+	for _, iface := range ifaces {
+		if iface.Name != best.iface {
+			continue
+		}
+		for _, a := range iface.Addrs {
+			if a.IP.To4() == nil {
+				continue
+			}
+			if route.Source == nil || a.Contains(hop) {
+				route.Source = a.IP.To4()
+			}
+			if a.Contains(hop) {
+				break
+			}
+		}
+	}
+	if route.Source == nil {
+		return nil, fmt.Errorf("route: no ipv4 address on interface %s", best.iface)
+	}
+	return route, nil
+}
+
+// routeFromDial asks the kernel for the source address by connecting a udp
+// socket, which sends nothing, for systems without a readable routing table
+func routeFromDial(dst net.IP, ifaces []Interface) (*Route, error) {
+	conn, err := net.Dial("udp4", net.JoinHostPort(dst.String(), "9"))
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	src := conn.LocalAddr().(*net.UDPAddr).IP.To4()
+	route := &Route{Source: src}
+	for _, iface := range ifaces {
+		for _, a := range iface.Addrs {
+			if a.IP.Equal(src) {
+				route.Interface = iface.Name
+			}
+		}
+	}
+	return route, nil
+}
+
+// targetAddress returns the local address to scan ip from. Addresses chosen
+// in opts are always used, otherwise the route to ip decides.
+func targetAddress(opts *ScanOptions, laddr string, ips []net.IP) (string, *Route) {
+	if opts.SourceIP != "" || opts.Interface != "" {
+		return laddr, nil
+	}
+
+	for _, ip := range ips {
+		if ip.To4() == nil {
+			continue
+		}
+		route, err := RouteTo(ip)
+		if err != nil {
+			break
+		}
+		return route.Source.String(), route
+	}
+	return laddr, nil
+}
+
+// defaultRouteTarget is a public address, routing to it finds the
+// default route. Nothing is sent to it.
+var defaultRouteTarget = net.IPv4(8, 8, 8, 8)
+
+// sourceAddress returns the local address to reach ip from, taken from the
+// routing table or the kernel. The first local address is a last resort for
+// hosts without a route to ip.
+func sourceAddress(ip net.IP) (string, error) {
+	if route, err := RouteTo(ip); err == nil {
+		return route.Source.String(), nil
+	}
+	if ifaces, err := Interfaces(); err == nil && ip.To4() != nil {
+		if route, err := routeFromDial(ip.To4(), ifaces); err == nil && route.Source != nil {
+			return route.Source.String(), nil
+		}
+	}
+	return getLocalIP()
+}
@@ -0,0 +1,95 @@
+package gomap
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"unsafe"
+)
+
+// Route flags from linux/route.h
+const (
+	rtfUp      = 0x1
+	rtfGateway = 0x2
+)
+
+// readRoutes parses the ipv4 routes in /proc/net/route
+func readRoutes() ([]routeEntry, error) {
+	f, err := os.Open("/proc/net/route")
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var routes []routeEntry
+	scanner := bufio.NewScanner(f)
+
+	// Skip the header line
+	scanner.Scan()
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 8 {
+			continue
+		}
+
+		flags, err := strconv.ParseUint(fields[3], 16, 16)
+		if err != nil || flags&rtfUp == 0 {
+			continue
+		}
+		dst, err := procIP(fields[1])
+		if err != nil {
+			return nil, err
+		}
+		gw, err := procIP(fields[2])
+		if err != nil {
+			return nil, err
+		}
+		mask, err := procIP(fields[7])
+		if err != nil {
+			return nil, err
+		}
+		metric, _ := strconv.Atoi(fields[6])
+
+		r := routeEntry{
+			iface:  fields[0],
+			dst:    net.IPNet{IP: dst, Mask: net.IPMask(mask)},
+			metric: metric,
+		}
+		if flags&rtfGateway != 0 {
+			r.gateway = gw
+		}
+		routes = append(routes, r)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return routes, nil
+}
+
+// procIP decodes an address from /proc/net/route which is printed
+// as hex in the byte order of the host
+func procIP(s string) (net.IP, error) {
+	v, err := strconv.ParseUint(s, 16, 32)
+	if err != nil {
+		return nil, fmt.Errorf("route: invalid address %q", s)
+	}
+
+	ip := make(net.IP, 4)
+	if littleEndian() {
+		binary.LittleEndian.PutUint32(ip, uint32(v))
+	} else {
+		binary.BigEndian.PutUint32(ip, uint32(v))
+	}
+	return ip, nil
+}
+
+// littleEndian reports the byte order of the host
+func littleEndian() bool {
+	x := uint16(1)
+	return *(*byte)(unsafe.Pointer(&x)) == 1
+}
@@ -0,0 +1,12 @@
+//go:build !linux
+// +build !linux
+
+package gomap
+
+import "fmt"
+
+// readRoutes is only supported on linux, other systems fall back to
+// letting the kernel pick the source address
+func readRoutes() ([]routeEntry, error) {
+	return nil, fmt.Errorf("route: reading the routing table is not supported")
+}
@@ -1,55 +1,386 @@
 package gomap
 
 import (
+	"errors"
 	"fmt"
 	"net"
 	"strconv"
+	"strings"
 	"time"
 )
 
 // scanIPRange scans an entire cidr range for open ports
 // I am fairly happy with this code since its just iterating
 // over scanIPPorts. Most issues are deeper in the code.
-func scanIPRange(laddr string, proto string, fastscan bool, stealth bool) (RangeScanResult, error) {
-	iprange := getLocalRange()
-	hosts := createHostRange(iprange)
+func scanIPRange(laddr string, opts *ScanOptions) (RangeScanResult, error) {
+	ranges, err := getLocalRanges(opts, laddr)
+	if err != nil {
+		return nil, err
+	}
 
-	var results RangeScanResult
-	for _, h := range hosts {
-		scan, err := scanIPPorts(h, laddr, proto, fastscan, stealth)
+	// Check the size first so a huge range is never expanded in memory
+	total := 0
+	for _, r := range ranges {
+		size, err := rangeSize(r)
 		if err != nil {
-			continue
+			return nil, err
 		}
-		results = append(results, scan)
+		total += size
+	}
+	if opts.MaxHosts > 0 && total > opts.MaxHosts {
+		return nil, fmt.Errorf("%w: %v contains %d hosts, more than MaxHosts (%d)", ErrRangeTooLarge, ranges, total, opts.MaxHosts)
 	}
 
-	return results, nil
+	var hosts []string
+	for _, r := range ranges {
+		h, err := createHostRange(r)
+		if err != nil {
+			return nil, err
+		}
+		hosts = append(hosts, h...)
+	}
+	// Discovery below already talks to the network
+	if err := checkScope(hosts, opts); err != nil {
+		return nil, err
+	}
+
+	a, err := startAudit(opts, "start", hosts)
+	if err != nil {
+		return nil, err
+	}
+
+	// Devices found over mDNS are named even without a PTR record
+	var mdns map[string][]MDNSService
+	if opts.MDNS {
+		services, err := discoverMDNS(laddr, 2*time.Second)
+		if err == nil {
+			mdns = mdnsHosts(services)
+		}
+		for ip := range mdns {
+			if !opts.Scope.allows(ip) {
+				delete(mdns, ip)
+			}
+		}
+		opts.Resolver.addHostNames(mdnsNames(mdns))
+	}
+
+	var upnp map[string][]UPnPDevice
+	if opts.SSDP {
+		devices, err := discoverSSDP(laddr, opts.Scope, 3*time.Second)
+		if err == nil {
+			upnp = upnpHosts(devices)
+		}
+	}
+
+	cp := newCheckpoint(*opts, hosts)
+	if cp != nil {
+		cp.MDNS = mdns
+		cp.UPnP = upnp
+	}
+	results, err := scanHosts(hosts, laddr, opts, cp)
+	addMDNS(results, mdns)
+	addUPnP(results, upnp)
+	return results, a.finish(results, err)
 }
 
-// scanIPPorts scans a list of ports on <hostname> <protocol>
-func scanIPPorts(hostname string, laddr string, proto string, fastscan bool, stealth bool) (*IPScanResult, error) {
-	var results []portResult
+// scanHosts scans each host in turn, recording progress in cp
+func scanHosts(hosts []string, laddr string, opts *ScanOptions, cp *checkpoint) (RangeScanResult, error) {
+	// A single target out of scope refuses the whole scan
+	if err := checkScope(hosts, opts); err != nil {
+		return nil, err
+	}
 
+	// Names are looked up in parallel up front instead of one at a time
+	// as each host is scanned
+	if !opts.NoDNS {
+		var ips []string
+		for _, h := range hosts {
+			if net.ParseIP(h) != nil {
+				ips = append(ips, h)
+			}
+		}
+		opts.Resolver.Resolve(ips, 50)
+	}
+
+	ctx := opts.context()
+	results := cp.results()
+	var scopeErr error
+	for i, h := range hosts {
+		// A canceled scan leaves its checkpoint unfinished so it can be resumed
+		if err := ctx.Err(); err != nil {
+			return results, err
+		}
+
+		scan, err := scanIPPorts(h, laddr, opts, cp)
+		if err != nil {
+			if ctx.Err() != nil {
+				return results, ctx.Err()
+			}
+			// Names that resolve out of scope are skipped and reported
+			// once the other hosts are done
+			if errors.Is(err, ErrOutOfScope) && scopeErr == nil {
+				scopeErr = err
+			}
+			scan = nil
+		} else {
+			results = append(results, scan)
+		}
+
+		if err := cp.hostDone(h, scan); err != nil {
+			return results, err
+		}
+		opts.progress(ScanProgress{Host: h, HostsDone: i + 1, HostsTotal: len(hosts)})
+	}
+
+	if err := cp.finish(); err != nil {
+		return results, err
+	}
+	return results, scopeErr
+}
+
+// scanIP scans a single host, recording it in a checkpoint if enabled
+func scanIP(hostname string, laddr string, opts *ScanOptions) (*IPScanResult, error) {
+	if err := checkScope([]string{hostname}, opts); err != nil {
+		return nil, err
+	}
+
+	cp := newCheckpoint(*opts, []string{hostname})
+	result, err := scanIPPorts(hostname, laddr, opts, cp)
+	if err != nil {
+		return nil, err
+	}
+	if err := cp.hostDone(hostname, result); err != nil {
+		return nil, err
+	}
+	return result, cp.finish()
+}
+
+// scanIPPorts scans a list of ports on <hostname> <protocol>
+func scanIPPorts(hostname string, laddr string, opts *ScanOptions, cp *checkpoint) (*IPScanResult, error) {
 	// checks if device is online
-	addr, err := net.LookupIP(hostname)
+	addr, err := opts.Resolver.LookupIP(hostname)
 	if err != nil {
 		return nil, err
 	}
+	target := pickAddress(addr, opts.Proto)
+	if err := opts.Scope.CheckAddress(hostname, net.ParseIP(target)); err != nil {
+		return nil, err
+	}
+
+	// Multi-homed hosts need the source address of the route to each target
+	hostLaddr, route := targetAddress(opts, laddr, addr)
+	if err := checkSourceAddress(hostLaddr, opts); err != nil {
+		return nil, err
+	}
 
 	// This gets the device name. ('/etc/hostname')
 	// This is typically a good indication of if a host is 'up'
 	// but can cause false-negatives in certain situations.
 	// For this reason when in fastscan mode, devices without
 	// names are ignored but are fully scanned in slowmode.
-	hname, err := net.LookupAddr(hostname)
-	if fastscan {
-		if err != nil {
+	var names []HostName
+	if !opts.NoDNS {
+		names, err = opts.Resolver.HostNames(target)
+	}
+	hname := "Unknown"
+	if len(names) > 0 {
+		hname = preferredName(names)
+	} else if net.ParseIP(hostname) == nil {
+		hname = hostname
+	} else if opts.Fastscan && !opts.NoDNS {
+		if err == nil {
+			err = fmt.Errorf("no names found for %s", hostname)
+		}
+		return nil, err
+	}
+
+	scan := &IPScanResult{
+		Hostname: hname,
+		Target:   hostname,
+		Names:    names,
+		IP:       addr,
+		Address:  net.ParseIP(target),
+		Route:    route,
+	}
+
+	// Names with several records (load balancers) have each address
+	// scanned on its own, otherwise the resolver picks one
+	targets := scanTargets(hostname, addr, opts)
+	if len(targets) > 1 {
+		if err := scanAddresses(hostname, targets, laddr, scan, opts, cp); err != nil {
 			return nil, err
 		}
-	} else if err != nil {
-		hname = append(hname, "Unknown")
+		return scan, nil
 	}
 
+	results, err := scanPorts(target, hostname, hostLaddr, opts, cp)
+	if err != nil {
+		return nil, err
+	}
+	inspectPorts(target, results, opts)
+	scan.Results = results
+	inspectHost(target, hostLaddr, scan, opts)
+	return scan, nil
+}
+
+// scanAddresses scans each of the targets hostname resolves to as a host
+// of its own, skipping those finished before the scan was resumed
+func scanAddresses(hostname string, targets []net.IP, laddr string, scan *IPScanResult, opts *ScanOptions, cp *checkpoint) error {
+	// Every address is checked before any is scanned
+	for _, ip := range targets {
+		if err := opts.Scope.CheckAddress(hostname, ip); err != nil {
+			return err
+		}
+	}
+
+	done := make(map[string]AddressResult)
+	for _, a := range cp.addresses(hostname) {
+		done[a.IP.String()] = a
+	}
+
+	for _, ip := range targets {
+		if a, ok := done[ip.String()]; ok {
+			scan.Addresses = append(scan.Addresses, a)
+			continue
+		}
+
+		ipLaddr, route := targetAddress(opts, laddr, []net.IP{ip})
+		if err := checkSourceAddress(ipLaddr, opts); err != nil {
+			return err
+		}
+		key := hostname + "/" + ip.String()
+		results, err := scanPorts(ip.String(), key, ipLaddr, opts, cp)
+		if err != nil {
+			return err
+		}
+		inspectPorts(ip.String(), results, opts)
+
+		host := &IPScanResult{
+			Hostname: scan.Hostname,
+			Names:    scan.Names,
+			IP:       []net.IP{ip},
+			Address:  ip,
+			Route:    route,
+			Results:  results,
+		}
+		inspectHost(ip.String(), ipLaddr, host, opts)
+
+		a := addressResult(host)
+		scan.Addresses = append(scan.Addresses, a)
+		if err := cp.addressDone(hostname, a); err != nil {
+			return err
+		}
+	}
+	scan.Results = mergeAddressResults(scan.Addresses)
+	return nil
+}
+
+// inspectHost runs the host probes and checks enabled in opts against
+// target once its ports are scanned. laddr is the local address to send from.
+func inspectHost(target string, laddr string, scan *IPScanResult, opts *ScanOptions) {
+	if opts.SMBInspection {
+		scan.NetBIOS, scan.SMB = inspectWindows(target, scan.Results)
+	}
+
+	if len(opts.SNMPCommunities) > 0 {
+		scan.SNMP, _ = querySNMP(target, opts.SNMPCommunities, 2*time.Second)
+	}
+
+	// A failed trace still leaves the port results usable
+	if opts.Traceroute {
+		port := tracePort(opts.TracerouteProto, scan.Results)
+		trace, err := traceroute(laddr, target, opts.TracerouteProto, port, opts.MaxHops, 2*time.Second)
+		if err == nil {
+			scan.Trace = trace
+		}
+	}
+
+	if opts.OSDetection {
+		open, closed := osPorts(scan.Results)
+		matches, err := detectOS(laddr, target, open, closed, 2*time.Second)
+		if err == nil {
+			scan.OS = matches
+		}
+	}
+
+	// Checks run last so they can use everything found about the host
+	if len(opts.Checks) > 0 {
+		runChecks(target, scan, opts)
+	}
+}
+
+// checkSourceAddress reports if raw sockets can be bound to the local
+// address picked for a target
+func checkSourceAddress(laddr string, opts *ScanOptions) error {
+	if opts.rawSockets() && !canSocketBind(laddr) {
+		return fmt.Errorf("socket: cannot bind %s: operation not permitted", laddr)
+	}
+	return nil
+}
+
+// scanTargets returns the addresses of hostname to scan separately when
+// opts.AllAddresses is set. Raw scans only support ipv4.
+func scanTargets(hostname string, addr []net.IP, opts *ScanOptions) []net.IP {
+	if !opts.AllAddresses || net.ParseIP(hostname) != nil {
+		return nil
+	}
+
+	var targets []net.IP
+	for _, ip := range addr {
+		if opts.Stealth && ip.To4() == nil {
+			continue
+		}
+		targets = append(targets, ip)
+	}
+	return targets
+}
+
+// inspectPorts runs the service inspection probes enabled in opts
+// against the open ports of target
+func inspectPorts(target string, results []portResult, opts *ScanOptions) {
+	if serviceProto(opts.Proto) != "tcp" {
+		return
+	}
+
+	// Versions come first so services on unusual ports are inspected too
+	if opts.VersionDetection {
+		detectVersions(target, results, opts)
+	}
+	if opts.Vulns != nil {
+		matchVulnerabilities(results, opts.Vulns)
+	}
+
+	for i, r := range results {
+		if addressState(r) != PortOpen {
+			continue
+		}
+		if opts.SSHInspection && (r.Service == "ssh" || r.Port == 22) {
+			address := net.JoinHostPort(target, strconv.Itoa(r.Port))
+			if info, err := inspectSSH(address, 5*time.Second); err == nil {
+				results[i].SSH = info
+			}
+		}
+	}
+}
+
+// pickAddress returns the address of a host to scan, matching the
+// address family of proto if possible
+func pickAddress(addr []net.IP, proto string) string {
+	v6 := strings.HasSuffix(proto, "6")
+	for _, ip := range addr {
+		if (ip.To4() == nil) == v6 {
+			return ip.String()
+		}
+	}
+	return addr[0].String()
+}
+
+// scanPorts scans the port list on target using a pool of workers.
+// key identifies the target in checkpoints.
+func scanPorts(target string, key string, laddr string, opts *ScanOptions, cp *checkpoint) ([]portResult, error) {
+	// Ports finished before a resumed scan was interrupted
+	results := cp.partial(key)
+
 	// Start prepping channels and vars for worker pool
 	in := make(chan int)
 	go func() {
@@ -59,28 +390,31 @@ func scanIPPorts(hostname string, laddr string, proto string, fastscan bool, ste
 		close(in)
 	}()
 
-	var list map[int]string
-	var depth int
-
-	if fastscan {
-		list = commonlist
+	list := opts.Services.portList(serviceProto(opts.Proto), opts.TopPorts)
+	depth := 500
+	if opts.Fastscan {
 		depth = 50
-	} else {
-		list = detailedlist
-		depth = 500
+	}
+
+	// Skip any ports restored from a checkpoint
+	for _, r := range results {
+		delete(list, r.Port)
 	}
 
 	tasks := len(list)
+	total := tasks + len(results)
 
-	// Create results channel and worker function
+	// Create results channel and worker function. Workers skip the
+	// remaining ports once the scan is canceled.
+	ctx := opts.context()
 	resultChannel := make(chan portResult, tasks)
 	worker := func() {
 		for port := range in {
-			if service, ok := list[port]; ok {
-				if stealth {
-					scanPortSyn(resultChannel, proto, hostname, service, port, laddr)
+			if service, ok := list[port]; ok && ctx.Err() == nil {
+				if opts.Stealth {
+					scanPortRaw(resultChannel, opts.ScanType, target, service, port, laddr)
 				} else {
-					scanPort(resultChannel, proto, hostname, service, port)
+					scanPort(resultChannel, opts, target, service, port, laddr)
 				}
 			}
 		}
@@ -91,59 +425,74 @@ func scanIPPorts(hostname string, laddr string, proto string, fastscan bool, ste
 		go worker()
 	}
 
-	// Combines all results from resultChannel and return a IPScanResult
-	for result := range resultChannel {
-		results = append(results, result)
-		fmt.Printf("\033[2K\rHost: %s | Ports Scanned %d/%d", hostname, len(results), tasks)
+	// Combines all results from resultChannel
+	for len(results) < total {
+		select {
+		case result := <-resultChannel:
+			results = append(results, result)
+			opts.progress(ScanProgress{Host: target, PortsDone: len(results), PortsTotal: total})
 
-		if len(results) == tasks {
-			close(resultChannel)
+			if err := cp.progress(key, results); err != nil {
+				return nil, err
+			}
+		case <-ctx.Done():
+			return nil, ctx.Err()
 		}
 	}
 
-	return &IPScanResult{
-		Hostname: hname[0],
-		IP:       addr,
-		Results:  results,
-	}, nil
+	return results, nil
+}
+
+// serviceProto strips the address family from a network name (tcp4 -> tcp)
+func serviceProto(proto string) string {
+	return strings.TrimRight(proto, "46")
 }
 
 // scanPort scans a single ip port combo
 // This detection method only works on some types of services
 // but is a reasonable solution for this application
-func scanPort(resultChannel chan<- portResult, protocol, hostname, service string, port int) {
+func scanPort(resultChannel chan<- portResult, opts *ScanOptions, hostname, service string, port int, laddr string) {
 	result := portResult{Port: port, Service: service}
-	address := hostname + ":" + strconv.Itoa(port)
-	conn, err := net.DialTimeout(protocol, address, 3*time.Second)
+	address := net.JoinHostPort(hostname, strconv.Itoa(port))
+
+	// Only bind to laddr when it was chosen so local targets still work
+	dialer := net.Dialer{Timeout: 3 * time.Second}
+	if opts.SourceIP != "" || opts.Interface != "" {
+		dialer.LocalAddr = localAddr(opts.Proto, laddr)
+	}
+	conn, err := dialer.Dial(opts.Proto, address)
 	if err != nil {
 		result.State = false
+		result.Status = PortClosed
+		if ne, ok := err.(net.Error); ok && ne.Timeout() {
+			result.Status = PortFiltered
+		}
 		resultChannel <- result
 		return
 	}
 
 	defer conn.Close()
 	result.State = true
+	result.Status = PortOpen
 	resultChannel <- result
 }
 
-// scanPortSyn scans a single ip port combo using a syn-ack
+// scanPortRaw scans a single ip port combo with a crafted tcp packet
 // This detection method again only works on some types of services
 // but is a reasonable solution for this application
-func scanPortSyn(resultChannel chan<- portResult, protocol, hostname, service string, port int, laddr string) {
+func scanPortRaw(resultChannel chan<- portResult, scan ScanType, hostname, service string, port int, laddr string) {
 	result := portResult{Port: port, Service: service}
-	ack := make(chan bool, 1)
 
-	go recvSynAck(laddr, hostname, uint16(port), ack)
-	sendSyn(laddr, hostname, uint16(random(10000, 65535)), uint16(port))
+	resp, err := probeTCP(laddr, hostname, uint16(port), probeFlags(scan), 3*time.Second)
+	if err != nil {
+		result.Status = PortFiltered
+	} else {
+		result.Status = classifyResponse(scan, resp)
+	}
 
-	select {
-	case r := <-ack:
-		result.State = r
-		resultChannel <- result
-		return
-	case <-time.After(3 * time.Second):
-		result.State = false
-		resultChannel <- result
-		return
+	switch result.Status {
+	case PortOpen, PortOpenOrFiltered, PortUnfiltered:
+		result.State = true
 	}
+	resultChannel <- result
 }
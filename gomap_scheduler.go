@@ -0,0 +1,468 @@
+package gomap
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ScheduledScan is a named scan run on a schedule
+type ScheduledScan struct {
+	// Name identifies the scan and names the directory its runs are
+	// stored in. It may contain letters, digits, '.', '_' and '-'.
+	Name string
+	// Schedule is a cron expression, see ParseSchedule
+	Schedule string
+	// Target is scanned with ScanIPWithOptions, or the local range with
+	// ScanRangeWithOptions when it is empty
+	Target  string `json:",omitempty"`
+	Options ScanOptions
+}
+
+// ScanRun is the stored outcome of one run of a scheduled scan
+type ScanRun struct {
+	Name     string
+	Started  time.Time
+	Finished time.Time
+	Error    string `json:",omitempty"`
+	Results  RangeScanResult
+	// Diff holds the changes since the previous successful run. It is
+	// nil for the first run and for runs that failed.
+	Diff *ScanDiff `json:",omitempty"`
+}
+
+// ScheduledScanStatus describes a scheduled scan and its last run. Secrets
+// such as SNMP communities are redacted from its Options.
+type ScheduledScanStatus struct {
+	ScheduledScan
+	Next    time.Time
+	Running bool
+	LastRun *time.Time `json:",omitempty"`
+	// LastError is the error of the last run or of storing it
+	LastError string `json:",omitempty"`
+	// Changes is the number of changes found by the last run
+	Changes int
+}
+
+// SchedulerOptions configures a Scheduler
+type SchedulerOptions struct {
+	// Dir is the directory the runs of each scan are stored in
+	Dir string
+	// History is the number of runs kept for each scan, 30 by default
+	History int
+	// OnRun is called once each run is stored
+	OnRun func(ScanRun) `json:"-"`
+	// Token is the bearer token every request must carry when set
+	Token string
+}
+
+// ErrScheduleNotFound is returned for a scheduled scan name that is not scheduled
+var ErrScheduleNotFound = errors.New("scheduled scan not found")
+
+// scanNameRegexp matches names that are safe to use as a directory name
+var scanNameRegexp = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+
+// runTimeFormat names the file of a run, it sorts in time order
+const runTimeFormat = "20060102T150405.000000000Z"
+
+// scheduledEntry is a scheduled scan with its state
+type scheduledEntry struct {
+	scan     ScheduledScan
+	schedule *Schedule
+	next     time.Time
+	running  bool
+	err      string
+	// last is the latest run and base the latest successful one
+	last *ScanRun
+	base *ScanRun
+}
+
+// Scheduler runs scans on cron schedules, storing each run along with
+// the changes since the previous run. It serves the list of scans and
+// their last results over HTTP:
+//
+//	GET /schedules                   list the scans
+//	GET /schedules/{name}            the status of a scan
+//	GET /schedules/{name}/last       the last run, ?format=xml for its results in XML
+type Scheduler struct {
+	opts SchedulerOptions
+	ctx  context.Context
+	stop context.CancelFunc
+	wg   sync.WaitGroup
+	wake chan struct{}
+
+	mu    sync.Mutex
+	scans map[string]*scheduledEntry
+}
+
+// NewScheduler starts a Scheduler storing runs in opts.Dir. Close stops it.
+func NewScheduler(opts SchedulerOptions) (*Scheduler, error) {
+	if opts.Dir == "" {
+		return nil, errors.New("scheduler: no directory to store runs in")
+	}
+	if opts.History <= 0 {
+		opts.History = 30
+	}
+	if err := os.MkdirAll(opts.Dir, 0700); err != nil {
+		return nil, err
+	}
+
+	s := &Scheduler{
+		opts:  opts,
+		wake:  make(chan struct{}, 1),
+		scans: make(map[string]*scheduledEntry),
+	}
+	s.ctx, s.stop = context.WithCancel(context.Background())
+	s.wg.Add(1)
+	go s.loop()
+	return s, nil
+}
+
+// Close stops scheduling, cancels running scans and waits for them to
+// stop. Canceled runs are not stored.
+func (s *Scheduler) Close() {
+	s.stop()
+	s.wg.Wait()
+}
+
+// Add schedules a scan. The last stored runs of a scan with the same
+// name are loaded so the next run is compared with them.
+func (s *Scheduler) Add(scan ScheduledScan) error {
+	if !scanNameRegexp.MatchString(scan.Name) {
+		return fmt.Errorf("scheduler: invalid name %q", scan.Name)
+	}
+	schedule, err := ParseSchedule(scan.Schedule)
+	if err != nil {
+		return err
+	}
+	next := schedule.Next(time.Now())
+	if next.IsZero() {
+		return fmt.Errorf("scheduler: schedule %q never runs", scan.Schedule)
+	}
+	if err := scan.Options.Scope.validate(); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Join(s.opts.Dir, scan.Name), 0700); err != nil {
+		return err
+	}
+	last, base, err := s.loadRuns(scan.Name)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.scans[scan.Name]; ok {
+		return fmt.Errorf("scheduler: scan %q already exists", scan.Name)
+	}
+	s.scans[scan.Name] = &scheduledEntry{
+		scan:     scan,
+		schedule: schedule,
+		next:     next,
+		last:     last,
+		base:     base,
+	}
+
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// Remove stops scheduling a scan. A running scan is finished and
+// stored runs are kept.
+func (s *Scheduler) Remove(name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.scans[name]; !ok {
+		return ErrScheduleNotFound
+	}
+	delete(s.scans, name)
+	return nil
+}
+
+// Scans lists the scheduled scans by name
+func (s *Scheduler) Scans() []ScheduledScanStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var list []ScheduledScanStatus
+	for _, e := range s.scans {
+		list = append(list, e.status())
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Name < list[j].Name
+	})
+	return list
+}
+
+// Scan returns the status of a scheduled scan
+func (s *Scheduler) Scan(name string) (ScheduledScanStatus, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e, ok := s.scans[name]
+	if !ok {
+		return ScheduledScanStatus{}, ErrScheduleNotFound
+	}
+	return e.status(), nil
+}
+
+// LastRun returns the last run of a scheduled scan, or nil if it has not run yet
+func (s *Scheduler) LastRun(name string) (*ScanRun, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e, ok := s.scans[name]
+	if !ok {
+		return nil, ErrScheduleNotFound
+	}
+	return e.last, nil
+}
+
+// status returns the status of the entry, the caller holds s.mu
+func (e *scheduledEntry) status() ScheduledScanStatus {
+	st := ScheduledScanStatus{
+		ScheduledScan: e.scan,
+		Next:          e.next,
+		Running:       e.running,
+		LastError:     e.err,
+	}
+	st.Options = *e.scan.Options.redacted()
+	if e.last != nil {
+		st.LastRun = &e.last.Started
+		if e.last.Diff != nil {
+			st.Changes = e.last.Diff.Len()
+		}
+	}
+	return st
+}
+
+// loop starts the scans that are due until the scheduler is closed
+func (s *Scheduler) loop() {
+	defer s.wg.Done()
+	for {
+		now := time.Now()
+		wait := time.Hour
+
+		s.mu.Lock()
+		for _, e := range s.scans {
+			if !e.next.After(now) {
+				// A scan still running when it is due again skips that run
+				if !e.running {
+					e.running = true
+					s.wg.Add(1)
+					go s.run(e)
+				}
+				e.next = e.schedule.Next(now)
+			}
+			if !e.next.IsZero() && e.next.Sub(now) < wait {
+				wait = e.next.Sub(now)
+			}
+		}
+		s.mu.Unlock()
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-s.ctx.Done():
+			timer.Stop()
+			return
+		case <-s.wake:
+		case <-timer.C:
+		}
+		timer.Stop()
+	}
+}
+
+// run scans a scheduled scan and stores the run
+func (s *Scheduler) run(e *scheduledEntry) {
+	defer s.wg.Done()
+
+	s.mu.Lock()
+	scan := e.scan
+	base := e.base
+	s.mu.Unlock()
+
+	run := ScanRun{Name: scan.Name, Started: time.Now()}
+	opts := scan.Options
+	opts.Context = s.ctx
+	if opts.Progress == nil {
+		opts.Progress = func(ScanProgress) {}
+	}
+	results, err := scanTarget(scan.Target, opts)
+	run.Finished = time.Now()
+	run.Results = results
+
+	if s.ctx.Err() != nil {
+		s.mu.Lock()
+		e.running = false
+		s.mu.Unlock()
+		return
+	}
+	if err != nil {
+		run.Error = err.Error()
+	} else if base != nil {
+		diff := DiffResults(base.Results, results)
+		run.Diff = &diff
+	}
+	storeErr := s.storeRun(run)
+
+	s.mu.Lock()
+	e.running = false
+	e.last = &run
+	if err == nil {
+		e.base = &run
+	}
+	e.err = run.Error
+	if storeErr != nil {
+		e.err = storeErr.Error()
+	}
+	s.mu.Unlock()
+
+	if s.opts.OnRun != nil {
+		s.opts.OnRun(run)
+	}
+}
+
+// storeRun writes a run to the directory of its scan and removes the
+// oldest runs beyond History
+func (s *Scheduler) storeRun(run ScanRun) error {
+	data, err := json.Marshal(run)
+	if err != nil {
+		return err
+	}
+
+	dir := filepath.Join(s.opts.Dir, run.Name)
+	path := filepath.Join(dir, run.Started.UTC().Format(runTimeFormat)+".json")
+	tmp := path + ".tmp"
+	if err := ioutil.WriteFile(tmp, data, 0600); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return err
+	}
+
+	files, err := runFiles(dir)
+	if err != nil {
+		return err
+	}
+	for len(files) > s.opts.History {
+		if err := os.Remove(filepath.Join(dir, files[0])); err != nil {
+			return err
+		}
+		files = files[1:]
+	}
+	return nil
+}
+
+// loadRuns reads the latest run and the latest successful run of a scan
+func (s *Scheduler) loadRuns(name string) (last, base *ScanRun, err error) {
+	dir := filepath.Join(s.opts.Dir, name)
+	files, err := runFiles(dir)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for i := len(files) - 1; i >= 0 && base == nil; i-- {
+		data, err := ioutil.ReadFile(filepath.Join(dir, files[i]))
+		if err != nil {
+			return nil, nil, err
+		}
+		run := &ScanRun{}
+		if err := json.Unmarshal(data, run); err != nil {
+			return nil, nil, fmt.Errorf("scheduler: %s: %v", files[i], err)
+		}
+		if last == nil {
+			last = run
+		}
+		if run.Error == "" {
+			base = run
+		}
+	}
+	return last, base, nil
+}
+
+// runFiles lists the stored runs in dir from oldest to newest
+func runFiles(dir string) ([]string, error) {
+	entries, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var files []string
+	for _, e := range entries {
+		if !e.IsDir() && strings.HasSuffix(e.Name(), ".json") {
+			files = append(files, e.Name())
+		}
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// ServeHTTP serves the list of scans and their last runs
+func (s *Scheduler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !authorized(w, r, s.opts.Token) {
+		return
+	}
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, errors.New("method not allowed"))
+		return
+	}
+
+	path := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	switch {
+	case path[0] != "schedules" || len(path) > 3 || (len(path) == 3 && path[2] != "last"):
+		writeError(w, http.StatusNotFound, errors.New("not found"))
+	case len(path) == 1:
+		writeJSON(w, http.StatusOK, s.Scans())
+	case len(path) == 2:
+		st, err := s.Scan(path[1])
+		if err != nil {
+			writeError(w, errorStatus(err), err)
+			return
+		}
+		writeJSON(w, http.StatusOK, st)
+	default:
+		s.serveLastRun(w, r, path[1])
+	}
+}
+
+// serveLastRun writes the last run of a scan as JSON or its results as XML
+func (s *Scheduler) serveLastRun(w http.ResponseWriter, r *http.Request, name string) {
+	run, err := s.LastRun(name)
+	if err != nil {
+		writeError(w, errorStatus(err), err)
+		return
+	}
+	if run == nil {
+		writeError(w, http.StatusNotFound, errors.New("scan has not run yet"))
+		return
+	}
+
+	switch r.URL.Query().Get("format") {
+	case "", "json":
+		writeJSON(w, http.StatusOK, run)
+	case "xml":
+		body, err := run.Results.Xml()
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err)
+			return
+		}
+		w.Header().Set("Content-Type", "application/xml")
+		w.Write([]byte(body))
+	default:
+		writeError(w, http.StatusBadRequest, errors.New("format must be json or xml"))
+	}
+}
@@ -0,0 +1,227 @@
+package gomap
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+)
+
+// ErrOutOfScope is wrapped by the ScopeError returned for targets outside ScanOptions.Scope
+var ErrOutOfScope = errors.New("target out of scope")
+
+// Scope limits the targets a scan may touch. Allow and Deny hold CIDR
+// ranges, addresses and domains. A domain such as example.com covers the
+// domain and its subdomains while *.example.com only covers subdomains.
+//
+// Deny always wins. Otherwise an address is in scope if it is in an
+// allowed range, or if it is not a public address (or AllowPublic is set)
+// and either it was reached through an allowed domain or Allow is empty.
+// A nil Scope is an empty one, so only public addresses are refused.
+type Scope struct {
+	Allow       []string
+	Deny        []string
+	AllowPublic bool
+}
+
+// ScopeError is returned for a target outside the scope of a scan
+type ScopeError struct {
+	Target string
+	// IP is the resolved address that was refused, if any
+	IP     net.IP `json:",omitempty"`
+	Reason string
+}
+
+func (e *ScopeError) Error() string {
+	if e.IP != nil && e.IP.String() != e.Target {
+		return fmt.Sprintf("%s: %s (%s): %s", ErrOutOfScope, e.Target, e.IP, e.Reason)
+	}
+	return fmt.Sprintf("%s: %s: %s", ErrOutOfScope, e.Target, e.Reason)
+}
+
+// Unwrap lets errors.Is match ErrOutOfScope
+func (e *ScopeError) Unwrap() error {
+	return ErrOutOfScope
+}
+
+// scopeRules are the parsed entries of a list
+type scopeRules struct {
+	nets    []*net.IPNet
+	domains []string
+}
+
+// parseScopeRules parses the entries of Allow or Deny
+func parseScopeRules(entries []string) (scopeRules, error) {
+	var rules scopeRules
+	for _, e := range entries {
+		e = strings.ToLower(strings.TrimSuffix(strings.TrimSpace(e), "."))
+		if e == "" {
+			continue
+		}
+		if strings.Contains(e, "/") {
+			_, n, err := net.ParseCIDR(e)
+			if err != nil {
+				return rules, fmt.Errorf("scope: invalid range %q", e)
+			}
+			rules.nets = append(rules.nets, n)
+			continue
+		}
+		if ip := net.ParseIP(e); ip != nil {
+			bits := 128
+			if ip.To4() != nil {
+				ip, bits = ip.To4(), 32
+			}
+			rules.nets = append(rules.nets, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+		rules.domains = append(rules.domains, e)
+	}
+	return rules, nil
+}
+
+// matchIP returns the range containing ip, if any
+func (r scopeRules) matchIP(ip net.IP) *net.IPNet {
+	for _, n := range r.nets {
+		if n.Contains(ip) {
+			return n
+		}
+	}
+	return nil
+}
+
+// matchName returns the domain covering name, if any
+func (r scopeRules) matchName(name string) string {
+	name = strings.ToLower(strings.TrimSuffix(name, "."))
+	for _, d := range r.domains {
+		if strings.HasPrefix(d, "*.") {
+			if strings.HasSuffix(name, d[1:]) {
+				return d
+			}
+		} else if name == d || strings.HasSuffix(name, "."+d) {
+			return d
+		}
+	}
+	return ""
+}
+
+// validate reports invalid entries in the scope
+func (s *Scope) validate() error {
+	if s == nil {
+		return nil
+	}
+	if _, err := parseScopeRules(s.Allow); err != nil {
+		return err
+	}
+	_, err := parseScopeRules(s.Deny)
+	return err
+}
+
+// CheckTarget reports if a target given to a scan, an address or a name,
+// may be scanned before it is resolved. Names are checked again once
+// resolved with CheckAddress.
+func (s *Scope) CheckTarget(target string) error {
+	if ip := net.ParseIP(target); ip != nil {
+		return s.CheckAddress(target, ip)
+	}
+	if s == nil {
+		return nil
+	}
+
+	deny, err := parseScopeRules(s.Deny)
+	if err != nil {
+		return err
+	}
+	if d := deny.matchName(target); d != "" {
+		return &ScopeError{Target: target, Reason: "denied by " + d}
+	}
+	return nil
+}
+
+// CheckAddress reports if ip, which target resolved to, may be scanned
+func (s *Scope) CheckAddress(target string, ip net.IP) error {
+	if s == nil {
+		s = &Scope{}
+	}
+	allow, err := parseScopeRules(s.Allow)
+	if err != nil {
+		return err
+	}
+	deny, err := parseScopeRules(s.Deny)
+	if err != nil {
+		return err
+	}
+
+	if n := deny.matchIP(ip); n != nil {
+		return &ScopeError{Target: target, IP: ip, Reason: "denied by " + n.String()}
+	}
+	if d := deny.matchName(target); d != "" {
+		return &ScopeError{Target: target, IP: ip, Reason: "denied by " + d}
+	}
+	if allow.matchIP(ip) != nil {
+		return nil
+	}
+	if isPublicIP(ip) && !s.AllowPublic {
+		return &ScopeError{Target: target, IP: ip, Reason: "public address not in an allowed range"}
+	}
+	if net.ParseIP(target) == nil && allow.matchName(target) != "" {
+		return nil
+	}
+	if len(allow.nets) > 0 || len(allow.domains) > 0 {
+		return &ScopeError{Target: target, IP: ip, Reason: "not in an allowed range or domain"}
+	}
+	return nil
+}
+
+// nonPublicNets are the ranges that are not routed on the internet
+var nonPublicNets = func() []*net.IPNet {
+	var nets []*net.IPNet
+	for _, cidr := range []string{
+		"0.0.0.0/8", "10.0.0.0/8", "100.64.0.0/10", "127.0.0.0/8",
+		"169.254.0.0/16", "172.16.0.0/12", "192.168.0.0/16",
+		"192.0.2.0/24", "198.18.0.0/15", "198.51.100.0/24", "203.0.113.0/24",
+		"224.0.0.0/4", "240.0.0.0/4",
+		"::/128", "::1/128", "fc00::/7", "fe80::/10", "ff00::/8", "2001:db8::/32",
+	} {
+		_, n, _ := net.ParseCIDR(cidr)
+		nets = append(nets, n)
+	}
+	return nets
+}()
+
+// isPublicIP reports if ip is a globally routed address
+func isPublicIP(ip net.IP) bool {
+	for _, n := range nonPublicNets {
+		if n.Contains(ip) {
+			return false
+		}
+	}
+	return true
+}
+
+// withAddresses returns a copy of the scope that also allows ips, for
+// addresses already checked against the names that resolved to them
+func (s *Scope) withAddresses(ips []string) *Scope {
+	scope := &Scope{}
+	if s != nil {
+		scope.Deny = append(scope.Deny, s.Deny...)
+		scope.Allow = append(scope.Allow, s.Allow...)
+		scope.AllowPublic = s.AllowPublic
+	}
+	scope.Allow = append(scope.Allow, ips...)
+	return scope
+}
+
+// allows reports if the address ip may be scanned
+func (s *Scope) allows(ip string) bool {
+	return s.CheckAddress(ip, net.ParseIP(ip)) == nil
+}
+
+// checkScope checks every host before a scan starts
+func checkScope(hosts []string, opts *ScanOptions) error {
+	for _, h := range hosts {
+		if err := opts.Scope.CheckTarget(h); err != nil {
+			return err
+		}
+	}
+	return nil
+}
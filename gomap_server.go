@@ -0,0 +1,583 @@
+package gomap
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrQueueFull is returned when a scan is submitted while every queue slot is taken
+	ErrQueueFull = errors.New("scan queue full")
+	// ErrServerClosed is returned when a scan is submitted after Close
+	ErrServerClosed = errors.New("server closed")
+	// ErrJobNotFound is returned for an unknown or expired job id
+	ErrJobNotFound = errors.New("job not found")
+	// ErrJobRunning is returned when the results of an unfinished job are requested
+	ErrJobRunning = errors.New("job not finished")
+	// ErrJobFinished is returned when a finished job is canceled
+	ErrJobFinished = errors.New("job already finished")
+	// ErrNotAllowed is returned for a request using checks or SNMP communities the server does not allow
+	ErrNotAllowed = errors.New("not allowed by the server")
+)
+
+// JobStatus is the state of a scan submitted to a Server
+type JobStatus string
+
+const (
+	JobQueued   JobStatus = "queued"
+	JobRunning  JobStatus = "running"
+	JobDone     JobStatus = "done"
+	JobFailed   JobStatus = "failed"
+	JobCanceled JobStatus = "canceled"
+)
+
+// ServerOptions configures a Server
+type ServerOptions struct {
+	// Workers is the number of scans run at once, 2 by default. QueueSize
+	// scans (100 by default) may wait for a worker, further ones are refused.
+	Workers   int
+	QueueSize int
+
+	// MaxJobs is the number of finished jobs kept with their results,
+	// 100 by default. The oldest are dropped first.
+	MaxJobs int
+
+	// Token is the bearer token every request must carry when set
+	Token string
+
+	// Scope, AuditLog, the databases, the local address and the DNS
+	// servers are applied to every scan and cannot be changed by a request.
+	// A server needs a Token, a Scope allowing ranges or domains, or both.
+	Scope      *Scope
+	AuditLog   string
+	Services   *ServiceDB
+	Probes     *ProbeDB
+	Vulns      *VulnDB
+	Interface  string
+	SourceIP   string
+	DNSServers []string
+
+	// MaxHosts caps the hosts a scan of the local range may cover, 4096 by
+	// default and -1 for no cap. Requests may lower it but not raise it.
+	MaxHosts int
+
+	// Checks and SNMPCommunities are the checks ("all" for every
+	// registered check) and communities requests may use. Requests naming
+	// others are refused.
+	Checks          []string
+	SNMPCommunities []string
+}
+
+// ScanRequest submits a scan of Target, or of the local range when
+// Target is empty. Operator is the operator named by the client, it is
+// not verified and recorded as ClaimedOperator.
+type ScanRequest struct {
+	Target   string
+	Operator string
+	Options  ScanOptions
+}
+
+// ScanJob describes a scan submitted to a Server. Operator is the identity
+// passed to Submit, the address of the client for the REST API, and
+// ClaimedOperator the unverified Operator of the ScanRequest.
+type ScanJob struct {
+	ID              string
+	Status          JobStatus
+	Target          string `json:",omitempty"`
+	Operator        string `json:",omitempty"`
+	ClaimedOperator string `json:",omitempty"`
+	Submitted       time.Time
+	Started         *time.Time `json:",omitempty"`
+	Finished        *time.Time `json:",omitempty"`
+	Progress        ScanProgress
+	Error           string `json:",omitempty"`
+}
+
+// job is a submitted scan along with its results
+type job struct {
+	ScanJob
+	opts    ScanOptions
+	results RangeScanResult
+	cancel  context.CancelFunc
+}
+
+// finished reports if the job will not change anymore
+func (j *job) finished() bool {
+	return j.Status != JobQueued && j.Status != JobRunning
+}
+
+// Server runs submitted scans on a fixed number of workers and serves a
+// REST API to submit, follow, cancel and fetch the results of scans:
+//
+//	POST   /scans                 submit a ScanRequest
+//	GET    /scans                 list the jobs
+//	GET    /scans/{id}            the status and progress of a job
+//	GET    /scans/{id}/results    the results, ?format=xml for XML
+//	DELETE /scans/{id}            cancel a job
+type Server struct {
+	opts  ServerOptions
+	queue chan *job
+	ctx   context.Context
+	stop  context.CancelFunc
+	wg    sync.WaitGroup
+
+	mu   sync.Mutex
+	jobs map[string]*job
+	// order holds the job ids from oldest to newest
+	order []string
+}
+
+// NewServer starts the workers of a Server. Close stops them.
+func NewServer(opts ServerOptions) (*Server, error) {
+	s, err := newServer(opts)
+	if err != nil {
+		return nil, err
+	}
+	for i := 0; i < s.opts.Workers; i++ {
+		s.wg.Add(1)
+		go s.work()
+	}
+	return s, nil
+}
+
+// newServer returns a Server without workers
+func newServer(opts ServerOptions) (*Server, error) {
+	if opts.Token == "" && (opts.Scope == nil || len(opts.Scope.Allow) == 0) {
+		return nil, errors.New("server: a token or a scope with allowed ranges is required")
+	}
+	if err := opts.Scope.validate(); err != nil {
+		return nil, err
+	}
+	if opts.Workers <= 0 {
+		opts.Workers = 2
+	}
+	if opts.QueueSize <= 0 {
+		opts.QueueSize = 100
+	}
+	if opts.MaxJobs <= 0 {
+		opts.MaxJobs = 100
+	}
+	if opts.MaxHosts == 0 {
+		opts.MaxHosts = 4096
+	}
+
+	s := &Server{
+		opts:  opts,
+		queue: make(chan *job, opts.QueueSize),
+		jobs:  make(map[string]*job),
+	}
+	s.ctx, s.stop = context.WithCancel(context.Background())
+	return s, nil
+}
+
+// Close cancels every queued and running scan and waits for the workers to stop
+func (s *Server) Close() {
+	// Submit checks the context under s.mu, so nothing is queued once it is canceled
+	s.mu.Lock()
+	s.stop()
+	s.mu.Unlock()
+	s.wg.Wait()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, j := range s.jobs {
+		if j.Status == JobQueued {
+			s.end(j, JobCanceled, context.Canceled)
+		}
+	}
+}
+
+// Submit queues a scan for operator, the verified identity of the client
+// recorded in the audit log. Options that read or write files on the server
+// or choose where it sends from are replaced by those of the server,
+// MaxHosts is capped and only the allowed checks and communities are used.
+func (s *Server) Submit(operator string, req ScanRequest) (ScanJob, error) {
+	if s.ctx.Err() != nil {
+		return ScanJob{}, ErrServerClosed
+	}
+	if req.Target != "" {
+		if err := s.opts.Scope.CheckTarget(req.Target); err != nil {
+			return ScanJob{}, err
+		}
+	}
+	if !containsString(s.opts.Checks, "all") {
+		if err := allowedNames("check", req.Options.Checks, s.opts.Checks); err != nil {
+			return ScanJob{}, err
+		}
+	}
+	if err := allowedNames("SNMP community", req.Options.SNMPCommunities, s.opts.SNMPCommunities); err != nil {
+		return ScanJob{}, err
+	}
+
+	opts := req.Options
+	opts.Checkpoint = ""
+	opts.ServiceFiles = nil
+	opts.ProbeFiles = nil
+	opts.VulnFeeds = nil
+	opts.Scope = s.opts.Scope
+	opts.AuditLog = s.opts.AuditLog
+	opts.Operator = operator
+	opts.ClaimedOperator = req.Operator
+	opts.Services = s.opts.Services
+	opts.Probes = s.opts.Probes
+	opts.Vulns = s.opts.Vulns
+	opts.Interface = s.opts.Interface
+	opts.SourceIP = s.opts.SourceIP
+	opts.DNSServers = s.opts.DNSServers
+	opts.Resolver = nil
+	opts.AllSubnets = false
+	if s.opts.MaxHosts > 0 && (opts.MaxHosts <= 0 || opts.MaxHosts > s.opts.MaxHosts) {
+		opts.MaxHosts = s.opts.MaxHosts
+	}
+	if err := opts.Scope.validate(); err != nil {
+		return ScanJob{}, err
+	}
+
+	id := make([]byte, 8)
+	if _, err := rand.Read(id); err != nil {
+		return ScanJob{}, err
+	}
+	j := &job{
+		ScanJob: ScanJob{
+			ID:              hex.EncodeToString(id),
+			Status:          JobQueued,
+			Target:          req.Target,
+			Operator:        operator,
+			ClaimedOperator: req.Operator,
+			Submitted:       time.Now(),
+		},
+		opts: opts,
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.ctx.Err() != nil {
+		return ScanJob{}, ErrServerClosed
+	}
+	select {
+	case s.queue <- j:
+	default:
+		return ScanJob{}, ErrQueueFull
+	}
+	s.jobs[j.ID] = j
+	s.order = append(s.order, j.ID)
+	s.prune()
+	return j.ScanJob, nil
+}
+
+// Jobs lists every job from oldest to newest
+func (s *Server) Jobs() []ScanJob {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	jobs := make([]ScanJob, 0, len(s.order))
+	for _, id := range s.order {
+		jobs = append(jobs, s.jobs[id].ScanJob)
+	}
+	return jobs
+}
+
+// Job returns the status of a job
+func (s *Server) Job(id string) (ScanJob, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	j, ok := s.jobs[id]
+	if !ok {
+		return ScanJob{}, ErrJobNotFound
+	}
+	return j.ScanJob, nil
+}
+
+// Results returns the results of a finished job. Failed and canceled
+// jobs return the hosts scanned before they stopped.
+func (s *Server) Results(id string) (RangeScanResult, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	j, ok := s.jobs[id]
+	if !ok {
+		return nil, ErrJobNotFound
+	}
+	if !j.finished() {
+		return nil, ErrJobRunning
+	}
+	return j.results, nil
+}
+
+// Cancel stops a queued or running job
+func (s *Server) Cancel(id string) (ScanJob, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	j, ok := s.jobs[id]
+	if !ok {
+		return ScanJob{}, ErrJobNotFound
+	}
+	switch j.Status {
+	case JobQueued:
+		s.end(j, JobCanceled, context.Canceled)
+	case JobRunning:
+		j.cancel()
+	default:
+		return j.ScanJob, ErrJobFinished
+	}
+	return j.ScanJob, nil
+}
+
+// work runs queued jobs until the server is closed
+func (s *Server) work() {
+	defer s.wg.Done()
+	for {
+		select {
+		case <-s.ctx.Done():
+			return
+		case j := <-s.queue:
+			s.run(j)
+		}
+	}
+}
+
+// run scans a job and records the outcome
+func (s *Server) run(j *job) {
+	ctx, cancel := context.WithCancel(s.ctx)
+	defer cancel()
+
+	s.mu.Lock()
+	// Jobs canceled while queued are skipped
+	if j.Status != JobQueued {
+		s.mu.Unlock()
+		return
+	}
+	now := time.Now()
+	j.Status = JobRunning
+	j.Started = &now
+	j.cancel = cancel
+	s.mu.Unlock()
+
+	opts := j.opts
+	opts.Context = ctx
+	opts.Progress = func(p ScanProgress) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if p.PortsTotal > 0 {
+			j.Progress.Host = p.Host
+			j.Progress.PortsDone = p.PortsDone
+			j.Progress.PortsTotal = p.PortsTotal
+		}
+		if p.HostsTotal > 0 {
+			j.Progress.HostsDone = p.HostsDone
+			j.Progress.HostsTotal = p.HostsTotal
+		}
+	}
+
+	results, err := scanTarget(j.Target, opts)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	j.results = results
+	switch {
+	case ctx.Err() != nil:
+		s.end(j, JobCanceled, ctx.Err())
+	case err != nil:
+		s.end(j, JobFailed, err)
+	default:
+		s.end(j, JobDone, nil)
+	}
+}
+
+// scanTarget scans target, or the local range when target is empty
+func scanTarget(target string, opts ScanOptions) (RangeScanResult, error) {
+	if target == "" {
+		return ScanRangeWithOptions(opts)
+	}
+	r, err := ScanIPWithOptions(target, opts)
+	if r == nil {
+		return nil, err
+	}
+	return RangeScanResult{r}, err
+}
+
+// end records the outcome of a job, the caller holds s.mu
+func (s *Server) end(j *job, status JobStatus, err error) {
+	now := time.Now()
+	j.Status = status
+	j.Finished = &now
+	j.cancel = nil
+	if err != nil {
+		j.Error = err.Error()
+	}
+	s.prune()
+}
+
+// prune drops the oldest finished jobs beyond MaxJobs, the caller holds s.mu
+func (s *Server) prune() {
+	finished := 0
+	for _, id := range s.order {
+		if s.jobs[id].finished() {
+			finished++
+		}
+	}
+
+	order := s.order[:0]
+	for _, id := range s.order {
+		if finished > s.opts.MaxJobs && s.jobs[id].finished() {
+			delete(s.jobs, id)
+			finished--
+			continue
+		}
+		order = append(order, id)
+	}
+	s.order = order
+}
+
+// ServeHTTP serves the REST API
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !authorized(w, r, s.opts.Token) {
+		return
+	}
+
+	path := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	if path[0] != "scans" || len(path) > 3 || (len(path) == 3 && path[2] != "results") {
+		writeError(w, http.StatusNotFound, errors.New("not found"))
+		return
+	}
+
+	switch {
+	case len(path) == 1 && r.Method == http.MethodGet:
+		writeJSON(w, http.StatusOK, s.Jobs())
+	case len(path) == 1 && r.Method == http.MethodPost:
+		s.serveSubmit(w, r)
+	case len(path) == 2 && r.Method == http.MethodGet:
+		j, err := s.Job(path[1])
+		if err != nil {
+			writeError(w, errorStatus(err), err)
+			return
+		}
+		writeJSON(w, http.StatusOK, j)
+	case len(path) == 2 && r.Method == http.MethodDelete:
+		j, err := s.Cancel(path[1])
+		if err != nil {
+			writeError(w, errorStatus(err), err)
+			return
+		}
+		writeJSON(w, http.StatusAccepted, j)
+	case len(path) == 3 && r.Method == http.MethodGet:
+		s.serveResults(w, r, path[1])
+	default:
+		writeError(w, http.StatusMethodNotAllowed, errors.New("method not allowed"))
+	}
+}
+
+// serveSubmit queues the scan in the body of r
+func (s *Server) serveSubmit(w http.ResponseWriter, r *http.Request) {
+	var req ScanRequest
+	dec := json.NewDecoder(http.MaxBytesReader(w, r.Body, 1<<20))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	// The token is shared, so the address of the client is the only identity
+	operator, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		operator = r.RemoteAddr
+	}
+
+	j, err := s.Submit(operator, req)
+	if err != nil {
+		writeError(w, errorStatus(err), err)
+		return
+	}
+	w.Header().Set("Location", "/scans/"+j.ID)
+	writeJSON(w, http.StatusAccepted, j)
+}
+
+// serveResults writes the results of a job as JSON or XML
+func (s *Server) serveResults(w http.ResponseWriter, r *http.Request, id string) {
+	results, err := s.Results(id)
+	if err != nil {
+		writeError(w, errorStatus(err), err)
+		return
+	}
+
+	var body, contentType string
+	switch r.URL.Query().Get("format") {
+	case "", "json":
+		body, err = results.Json()
+		contentType = "application/json"
+	case "xml":
+		body, err = results.Xml()
+		contentType = "application/xml"
+	default:
+		writeError(w, http.StatusBadRequest, errors.New("format must be json or xml"))
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	w.Header().Set("Content-Type", contentType)
+	w.Write([]byte(body))
+}
+
+// authorized reports if r carries token, answering it when it does not
+func authorized(w http.ResponseWriter, r *http.Request, token string) bool {
+	if token == "" {
+		return true
+	}
+	got := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if subtle.ConstantTimeCompare([]byte(got), []byte(token)) != 1 {
+		w.Header().Set("WWW-Authenticate", "Bearer")
+		writeError(w, http.StatusUnauthorized, errors.New("invalid token"))
+		return false
+	}
+	return true
+}
+
+// allowedNames returns an error naming the first of names not in allowed
+func allowedNames(kind string, names, allowed []string) error {
+	for _, name := range names {
+		if !containsString(allowed, name) {
+			return fmt.Errorf("%w: %s %q", ErrNotAllowed, kind, name)
+		}
+	}
+	return nil
+}
+
+// errorStatus returns the HTTP status for an error of the server
+func errorStatus(err error) int {
+	switch {
+	case errors.Is(err, ErrJobNotFound), errors.Is(err, ErrScheduleNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, ErrJobRunning), errors.Is(err, ErrJobFinished):
+		return http.StatusConflict
+	case errors.Is(err, ErrOutOfScope), errors.Is(err, ErrNotAllowed):
+		return http.StatusForbidden
+	case errors.Is(err, ErrQueueFull), errors.Is(err, ErrServerClosed):
+		return http.StatusServiceUnavailable
+	}
+	return http.StatusBadRequest
+}
+
+// writeJSON writes v as the body of a response
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+// writeError writes err as the body of a response
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, struct{ Error string }{err.Error()})
+}
@@ -0,0 +1,249 @@
+package gomap
+
+import (
+	"bufio"
+	_ "embed"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+//go:embed data/gomap-services
+var defaultServicesFile string
+
+var (
+	defaultServices     *ServiceDB
+	defaultServicesOnce sync.Once
+)
+
+// commonPorts are ranked first among ports without an open frequency,
+// the embedded database has none
+var commonPorts = map[int]bool{
+	7: true, 20: true, 21: true, 22: true, 23: true, 25: true, 43: true, 50: true,
+	51: true, 53: true, 67: true, 68: true, 69: true, 80: true, 88: true, 110: true,
+	111: true, 115: true, 119: true, 123: true, 137: true, 138: true, 139: true, 143: true,
+	156: true, 161: true, 162: true, 389: true, 443: true, 513: true, 540: true, 546: true,
+	547: true, 554: true, 587: true, 873: true, 902: true, 989: true, 990: true, 1194: true,
+	1725: true, 1812: true, 1813: true, 2049: true, 2082: true, 2083: true, 3306: true, 3389: true,
+	5000: true, 5432: true, 5500: true, 5900: true, 5938: true, 8000: true, 8080: true, 8333: true,
+	8443: true, 8998: true, 9030: true, 9050: true, 9051: true, 9150: true, 9200: true, 9418: true,
+	9987: true, 19999: true, 23399: true, 25565: true, 27017: true, 28015: true, 43594: true, 43595: true,
+}
+
+// Service contains a single entry of a service database
+type Service struct {
+	Name      string
+	Port      int
+	Protocol  string
+	Frequency float64
+	Comment   string
+}
+
+// ServiceDB maps ports to the services that commonly run on them.
+// It is loaded from files in the nmap-services format.
+type ServiceDB struct {
+	mu       sync.RWMutex
+	services map[string]map[int]Service
+}
+
+// NewServiceDB returns an empty service database
+func NewServiceDB() *ServiceDB {
+	return &ServiceDB{services: make(map[string]map[int]Service)}
+}
+
+// DefaultServices returns the service database embedded in gomap. It is
+// shared and must not be changed, Merge it into a NewServiceDB to extend it.
+func DefaultServices() *ServiceDB {
+	defaultServicesOnce.Do(func() {
+		db, err := ParseServices(strings.NewReader(defaultServicesFile))
+		if err != nil {
+			panic("gomap: invalid embedded service database: " + err.Error())
+		}
+		defaultServices = db
+	})
+	return defaultServices
+}
+
+// LoadServices reads a service database from an nmap-services formatted file
+func LoadServices(path string) (*ServiceDB, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return ParseServices(f)
+}
+
+// ParseServices parses a service database in the nmap-services format:
+//
+//	<service name> <port>/<protocol> [<open frequency>] [# <comment>]
+func ParseServices(r io.Reader) (*ServiceDB, error) {
+	db := NewServiceDB()
+	scanner := bufio.NewScanner(r)
+
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := scanner.Text()
+
+		var comment string
+		if i := strings.Index(text, "#"); i >= 0 {
+			comment = strings.TrimSpace(text[i+1:])
+			text = text[:i]
+		}
+
+		fields := strings.Fields(text)
+		if len(fields) == 0 {
+			continue
+		}
+		if len(fields) < 2 {
+			return nil, fmt.Errorf("services: line %d: missing port/protocol", line)
+		}
+
+		portProto := strings.SplitN(fields[1], "/", 2)
+		if len(portProto) != 2 {
+			return nil, fmt.Errorf("services: line %d: invalid port/protocol %q", line, fields[1])
+		}
+		port, err := strconv.Atoi(portProto[0])
+		if err != nil || port < 0 || port > 65535 {
+			return nil, fmt.Errorf("services: line %d: invalid port %q", line, portProto[0])
+		}
+
+		s := Service{
+			Name:     fields[0],
+			Port:     port,
+			Protocol: strings.ToLower(portProto[1]),
+			Comment:  comment,
+		}
+
+		// Older nmap-services files have no frequency column
+		if len(fields) > 2 {
+			s.Frequency, err = strconv.ParseFloat(fields[2], 64)
+			if err != nil {
+				return nil, fmt.Errorf("services: line %d: invalid frequency %q", line, fields[2])
+			}
+		}
+		db.Set(s)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return db, nil
+}
+
+// Set adds a service to the database, replacing any entry on the same port and protocol
+func (db *ServiceDB) Set(s Service) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	s.Protocol = strings.ToLower(s.Protocol)
+	if db.services[s.Protocol] == nil {
+		db.services[s.Protocol] = make(map[int]Service)
+	}
+	db.services[s.Protocol][s.Port] = s
+}
+
+// Merge copies every service in other into db, overriding existing entries
+func (db *ServiceDB) Merge(other *ServiceDB) {
+	for _, s := range other.All() {
+		db.Set(s)
+	}
+}
+
+// Lookup returns the service registered on port for proto. If proto has no
+// entry for the port a service on another protocol is returned as a guess.
+func (db *ServiceDB) Lookup(port int, proto string) (Service, bool) {
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+
+	if s, ok := db.services[strings.ToLower(proto)][port]; ok {
+		return s, true
+	}
+	for _, p := range db.protocols() {
+		if s, ok := db.services[p][port]; ok {
+			return s, true
+		}
+	}
+	return Service{}, false
+}
+
+// Name returns the service name for port or "unknown" if there is none
+func (db *ServiceDB) Name(port int, proto string) string {
+	if s, ok := db.Lookup(port, proto); ok {
+		return s.Name
+	}
+	return "unknown"
+}
+
+// All returns every service in the database ordered by protocol and port
+func (db *ServiceDB) All() []Service {
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+
+	var all []Service
+	for _, p := range db.protocols() {
+		for _, s := range db.services[p] {
+			all = append(all, s)
+		}
+	}
+
+	sort.Slice(all, func(i, j int) bool {
+		if all[i].Protocol != all[j].Protocol {
+			return all[i].Protocol < all[j].Protocol
+		}
+		return all[i].Port < all[j].Port
+	})
+	return all
+}
+
+// Top returns the n services on proto with the highest open frequency.
+// Services with the same frequency are ranked common ports first and then
+// by port. If n is less than 1 every service on proto is returned.
+func (db *ServiceDB) Top(proto string, n int) []Service {
+	db.mu.RLock()
+	var top []Service
+	for _, s := range db.services[strings.ToLower(proto)] {
+		top = append(top, s)
+	}
+	db.mu.RUnlock()
+
+	sort.Slice(top, func(i, j int) bool {
+		if top[i].Frequency != top[j].Frequency {
+			return top[i].Frequency > top[j].Frequency
+		}
+		if commonPorts[top[i].Port] != commonPorts[top[j].Port] {
+			return commonPorts[top[i].Port]
+		}
+		return top[i].Port < top[j].Port
+	})
+
+	if n > 0 && n < len(top) {
+		top = top[:n]
+	}
+	return top
+}
+
+// portList returns a map of port to service name for the top n ports on proto
+func (db *ServiceDB) portList(proto string, n int) map[int]string {
+	list := make(map[int]string)
+	for _, s := range db.Top(proto, n) {
+		list[s.Port] = s.Name
+	}
+	return list
+}
+
+// protocols returns the protocols in the database in a stable order
+func (db *ServiceDB) protocols() []string {
+	var protos []string
+	for p := range db.services {
+		protos = append(protos, p)
+	}
+	sort.Strings(protos)
+	return protos
+}
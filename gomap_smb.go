@@ -0,0 +1,366 @@
+package gomap
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/JustinTimperio/gomap/packet"
+)
+
+// NetBIOSInfo is the answer to a NetBIOS node status query
+type NetBIOSInfo struct {
+	// Name is the computer name and Workgroup its workgroup or domain
+	Name      string
+	Workgroup string
+	MAC       net.HardwareAddr
+	Names     []NetBIOSName
+}
+
+// NetBIOSName is a name registered by a host. Suffix is the type of
+// service, for example 0x00 for the workstation and 0x20 for file sharing.
+type NetBIOSName struct {
+	Name   string
+	Suffix byte
+	Group  bool
+}
+
+// SMBInfo describes what an SMB server agreed to during negotiation
+type SMBInfo struct {
+	Dialects        []string
+	SigningRequired bool
+}
+
+// String with the name and workgroup
+func (n *NetBIOSInfo) String() string {
+	if n.Workgroup == "" {
+		return n.Name
+	}
+	return n.Workgroup + `\` + n.Name
+}
+
+// String with the dialects and signing requirement
+func (s *SMBInfo) String() string {
+	signing := "not required"
+	if s.SigningRequired {
+		signing = "required"
+	}
+	return fmt.Sprintf("%s (signing %s)", strings.Join(s.Dialects, ", "), signing)
+}
+
+// smb2Dialects are the SMB2 dialect revisions tried in turn
+var smb2Dialects = []struct {
+	revision uint16
+	name     string
+}{
+	{0x0202, "SMB 2.0.2"},
+	{0x0210, "SMB 2.1"},
+	{0x0300, "SMB 3.0"},
+	{0x0302, "SMB 3.0.2"},
+	{0x0311, "SMB 3.1.1"},
+}
+
+// QueryNetBIOS sends a NetBIOS node status request to udp port 137 of hostname
+func QueryNetBIOS(hostname string) (*NetBIOSInfo, error) {
+	return queryNetBIOS(hostname, 2*time.Second)
+}
+
+// InspectSMB finds the SMB dialects supported by the server on port and
+// whether it requires signing
+func InspectSMB(hostname string, port int) (*SMBInfo, error) {
+	return inspectSMB(net.JoinHostPort(hostname, strconv.Itoa(port)), 5*time.Second)
+}
+
+// inspectWindows runs the NetBIOS and SMB queries against target when
+// one of the ports used by Windows file sharing is open
+func inspectWindows(target string, results []portResult) (*NetBIOSInfo, *SMBInfo) {
+	var netbios *NetBIOSInfo
+	var smb *SMBInfo
+	for _, r := range results {
+		if addressState(r) != PortOpen {
+			continue
+		}
+		switch r.Port {
+		case 137, 138, 139, 445:
+			if netbios == nil {
+				netbios, _ = QueryNetBIOS(target)
+			}
+		}
+		if r.Port == 445 {
+			smb, _ = InspectSMB(target, r.Port)
+		}
+	}
+	return netbios, smb
+}
+
+// queryNetBIOS asks for the names table of hostname
+func queryNetBIOS(hostname string, timeout time.Duration) (*NetBIOSInfo, error) {
+	conn, err := net.DialTimeout("udp", net.JoinHostPort(hostname, "137"), timeout)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(timeout))
+
+	// The wildcard name "*" in first level encoding
+	query := packet.DNS{
+		ID:        uint16(random(1, 65535)),
+		Questions: []packet.DNSQuestion{{Name: "CK" + strings.Repeat("A", 30), Type: 0x21, Class: packet.DNSClassINET}},
+	}
+	msg, err := query.Marshal()
+	if err != nil {
+		return nil, err
+	}
+	if _, err := conn.Write(msg); err != nil {
+		return nil, err
+	}
+
+	buff := make([]byte, 1500)
+	for {
+		n, err := conn.Read(buff)
+		if err != nil {
+			return nil, err
+		}
+		data, err := nodeStatusAnswer(buff[:n], query.ID)
+		if err != nil {
+			continue
+		}
+		return parseNodeStatus(data)
+	}
+}
+
+// nodeStatusAnswer returns the data of the first answer of a node status
+// response. The DNS parser is not used as the NBSTAT type shares its
+// value with SRV.
+func nodeStatusAnswer(b []byte, id uint16) ([]byte, error) {
+	if len(b) < 12 {
+		return nil, packet.ErrTruncated
+	}
+	if binary.BigEndian.Uint16(b[0:2]) != id || b[2]&0x80 == 0 {
+		return nil, errors.New("netbios: not a reply to the query")
+	}
+	if binary.BigEndian.Uint16(b[6:8]) == 0 {
+		return nil, errors.New("netbios: no answer")
+	}
+
+	off := 12
+	for i := 0; i < int(binary.BigEndian.Uint16(b[4:6])); i++ {
+		var err error
+		if off, err = skipNetBIOSName(b, off); err != nil {
+			return nil, err
+		}
+		off += 4
+	}
+	off, err := skipNetBIOSName(b, off)
+	if err != nil {
+		return nil, err
+	}
+
+	// Type, class and TTL come before the data length
+	if off+10 > len(b) {
+		return nil, packet.ErrTruncated
+	}
+	length := int(binary.BigEndian.Uint16(b[off+8 : off+10]))
+	off += 10
+	if off+length > len(b) {
+		return nil, packet.ErrTruncated
+	}
+	return b[off : off+length], nil
+}
+
+// skipNetBIOSName returns the offset after the name at off
+func skipNetBIOSName(b []byte, off int) (int, error) {
+	for off < len(b) {
+		l := int(b[off])
+		switch {
+		case l == 0:
+			return off + 1, nil
+		case l&0xc0 == 0xc0:
+			return off + 2, nil
+		}
+		off += 1 + l
+	}
+	return 0, packet.ErrTruncated
+}
+
+// parseNodeStatus decodes the names and MAC address of a node status answer
+func parseNodeStatus(data []byte) (*NetBIOSInfo, error) {
+	if len(data) < 1 {
+		return nil, packet.ErrTruncated
+	}
+	count := int(data[0])
+	if len(data) < 1+count*18 {
+		return nil, packet.ErrTruncated
+	}
+
+	info := &NetBIOSInfo{}
+	for i := 0; i < count; i++ {
+		entry := data[1+i*18 : 1+(i+1)*18]
+		name := NetBIOSName{
+			Name:   strings.TrimRight(string(entry[:15]), " \x00"),
+			Suffix: entry[15],
+			Group:  binary.BigEndian.Uint16(entry[16:18])&0x8000 != 0,
+		}
+		info.Names = append(info.Names, name)
+
+		if name.Suffix != 0x00 {
+			continue
+		}
+		if name.Group && info.Workgroup == "" {
+			info.Workgroup = name.Name
+		} else if !name.Group && info.Name == "" {
+			info.Name = name.Name
+		}
+	}
+
+	if mac := data[1+count*18:]; len(mac) >= 6 && !bytes.Equal(mac[:6], make([]byte, 6)) {
+		info.MAC = net.HardwareAddr(append([]byte{}, mac[:6]...))
+	}
+	return info, nil
+}
+
+// inspectSMB negotiates once for SMB1 and once for each SMB2 dialect
+// since servers only ever answer with the highest one they share
+func inspectSMB(address string, timeout time.Duration) (*SMBInfo, error) {
+	info := &SMBInfo{}
+	var lastErr error
+
+	required, err := negotiateSMB1(address, timeout)
+	if err == nil {
+		info.Dialects = append(info.Dialects, "SMB 1.0")
+		info.SigningRequired = info.SigningRequired || required
+	} else {
+		lastErr = err
+	}
+
+	for _, d := range smb2Dialects {
+		required, err := negotiateSMB2(address, d.revision, timeout)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		info.Dialects = append(info.Dialects, d.name)
+		info.SigningRequired = info.SigningRequired || required
+	}
+
+	if len(info.Dialects) == 0 {
+		return nil, lastErr
+	}
+	return info, nil
+}
+
+// smbExchange sends msg with a NetBIOS session header and reads the reply
+func smbExchange(address string, msg []byte, timeout time.Duration) ([]byte, error) {
+	conn, err := net.DialTimeout("tcp", address, timeout)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(timeout))
+
+	header := []byte{0, byte(len(msg) >> 16), byte(len(msg) >> 8), byte(len(msg))}
+	if _, err := conn.Write(append(header, msg...)); err != nil {
+		return nil, err
+	}
+
+	if _, err := io.ReadFull(conn, header); err != nil {
+		return nil, err
+	}
+	resp := make([]byte, int(header[1])<<16|int(header[2])<<8|int(header[3]))
+	if _, err := io.ReadFull(conn, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// negotiateSMB1 offers the NT LM 0.12 dialect and returns whether signing is required
+func negotiateSMB1(address string, timeout time.Duration) (bool, error) {
+	msg := make([]byte, 32)
+	copy(msg, "\xffSMB")
+	msg[4] = 0x72                                     // negotiate
+	msg[9] = 0x18                                     // case insensitive, canonical paths
+	binary.LittleEndian.PutUint16(msg[10:12], 0xc001) // unicode, nt status, long names
+	msg = append(msg, 0)                              // word count
+	dialect := append([]byte{0x02}, "NT LM 0.12\x00"...)
+	msg = append(msg, byte(len(dialect)), byte(len(dialect)>>8))
+	msg = append(msg, dialect...)
+
+	resp, err := smbExchange(address, msg, timeout)
+	if err != nil {
+		return false, err
+	}
+	return parseSMB1Negotiate(resp)
+}
+
+// parseSMB1Negotiate returns whether the server requires signing from an
+// SMB1 negotiate response
+func parseSMB1Negotiate(resp []byte) (bool, error) {
+	if len(resp) < 36 || !bytes.Equal(resp[:4], []byte("\xffSMB")) || resp[32] == 0 {
+		return false, errors.New("smb: SMB1 not supported")
+	}
+	if binary.LittleEndian.Uint16(resp[33:35]) == 0xffff {
+		return false, errors.New("smb: SMB1 dialect refused")
+	}
+	return resp[35]&0x08 != 0, nil
+}
+
+// negotiateSMB2 offers a single SMB2 dialect and returns whether signing is required
+func negotiateSMB2(address string, dialect uint16, timeout time.Duration) (bool, error) {
+	msg := make([]byte, 64+36)
+	copy(msg, "\xfeSMB")
+	binary.LittleEndian.PutUint16(msg[4:6], 64)  // header size
+	binary.LittleEndian.PutUint16(msg[14:16], 1) // credits requested
+
+	body := msg[64:]
+	binary.LittleEndian.PutUint16(body[0:2], 36)
+	binary.LittleEndian.PutUint16(body[2:4], 1) // dialect count
+	binary.LittleEndian.PutUint16(body[4:6], 1) // signing enabled
+	rand.Read(body[12:28])                      // client guid
+	msg = append(msg, byte(dialect), byte(dialect>>8))
+
+	// SMB 3.1.1 must carry a preauth integrity context
+	if dialect == 0x0311 {
+		for len(msg)%8 != 0 {
+			msg = append(msg, 0)
+		}
+		binary.LittleEndian.PutUint32(msg[64+28:64+32], uint32(len(msg)))
+		binary.LittleEndian.PutUint16(msg[64+32:64+34], 1)
+
+		ctx := make([]byte, 8+6+32)
+		binary.LittleEndian.PutUint16(ctx[0:2], 1) // preauth integrity
+		binary.LittleEndian.PutUint16(ctx[2:4], uint16(len(ctx)-8))
+		binary.LittleEndian.PutUint16(ctx[8:10], 1)   // hash count
+		binary.LittleEndian.PutUint16(ctx[10:12], 32) // salt length
+		binary.LittleEndian.PutUint16(ctx[12:14], 1)  // sha512
+		rand.Read(ctx[14:])
+		msg = append(msg, ctx...)
+	}
+
+	resp, err := smbExchange(address, msg, timeout)
+	if err != nil {
+		return false, err
+	}
+	return parseSMB2Negotiate(resp, dialect)
+}
+
+// parseSMB2Negotiate returns whether the server requires signing from an
+// SMB2 negotiate response, which must accept dialect
+func parseSMB2Negotiate(resp []byte, dialect uint16) (bool, error) {
+	if len(resp) < 64+6 || !bytes.Equal(resp[:4], []byte("\xfeSMB")) {
+		return false, errors.New("smb: SMB2 not supported")
+	}
+	if status := binary.LittleEndian.Uint32(resp[8:12]); status != 0 {
+		return false, fmt.Errorf("smb: negotiate failed with status %#08x", status)
+	}
+	if binary.LittleEndian.Uint16(resp[68:70]) != dialect {
+		return false, fmt.Errorf("smb: dialect %#04x refused", dialect)
+	}
+	return binary.LittleEndian.Uint16(resp[66:68])&0x02 != 0, nil
+}
@@ -0,0 +1,378 @@
+package gomap
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SNMPInfo is the system information and interface table read over SNMP
+type SNMPInfo struct {
+	// Community is the index of the community that worked in the list
+	// tried, the community itself is a secret and not kept
+	Community   int
+	Version     string
+	SysDescr    string
+	SysName     string
+	SysObjectID string
+	SysUpTime   time.Duration
+	Interfaces  []SNMPInterface
+}
+
+// SNMPInterface is an entry of the interface table (IF-MIB ifTable)
+type SNMPInterface struct {
+	Index       int
+	Descr       string
+	Type        int
+	MTU         int
+	Speed       uint64
+	PhysAddress net.HardwareAddr
+	// AdminStatus and OperStatus are 1 for up and 2 for down
+	AdminStatus int
+	OperStatus  int
+}
+
+// String with the system name and description
+func (s *SNMPInfo) String() string {
+	descr := strings.Join(strings.Fields(s.SysDescr), " ")
+	return fmt.Sprintf("%s %s (%s, up %s, %d interfaces)", s.SysName, descr, s.Version, s.SysUpTime, len(s.Interfaces))
+}
+
+// SNMP object identifiers
+const (
+	oidSysDescr    = "1.3.6.1.2.1.1.1.0"
+	oidSysObjectID = "1.3.6.1.2.1.1.2.0"
+	oidSysUpTime   = "1.3.6.1.2.1.1.3.0"
+	oidSysName     = "1.3.6.1.2.1.1.5.0"
+	oidIfEntry     = "1.3.6.1.2.1.2.2.1"
+)
+
+// BER tags used by SNMP
+const (
+	berInteger     = 0x02
+	berOctetString = 0x04
+	berNull        = 0x05
+	berOID         = 0x06
+	berSequence    = 0x30
+	berEndOfMib    = 0x82
+
+	snmpGet      = 0xa0
+	snmpGetNext  = 0xa1
+	snmpResponse = 0xa2
+)
+
+// snmpVarbind is a value returned by an agent
+type snmpVarbind struct {
+	OID   string
+	Tag   byte
+	Value []byte
+}
+
+// QuerySNMP tries each community with SNMP v2c and then v1 and reads
+// the system group and interface table with the first that works
+func QuerySNMP(hostname string, communities []string) (*SNMPInfo, error) {
+	return querySNMP(hostname, communities, 2*time.Second)
+}
+
+// querySNMP returns the information read with the first working community
+func querySNMP(hostname string, communities []string, timeout time.Duration) (*SNMPInfo, error) {
+	conn, err := net.DialTimeout("udp", net.JoinHostPort(hostname, "161"), timeout)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	lastErr := errors.New("snmp: no communities")
+	for i, community := range communities {
+		for _, version := range []int{1, 0} {
+			c := &snmpClient{conn: conn, community: community, version: version, timeout: timeout}
+			vars, err := c.request(snmpGet, []string{oidSysDescr, oidSysObjectID, oidSysUpTime, oidSysName})
+			if err != nil {
+				lastErr = err
+				continue
+			}
+
+			info := &SNMPInfo{Community: i, Version: []string{"v1", "v2c"}[version]}
+			for _, v := range vars {
+				switch v.OID {
+				case oidSysDescr:
+					info.SysDescr = string(v.Value)
+				case oidSysObjectID:
+					info.SysObjectID, _ = berDecodeOID(v.Value)
+				case oidSysUpTime:
+					info.SysUpTime = time.Duration(berUint(v.Value)) * 10 * time.Millisecond
+				case oidSysName:
+					info.SysName = string(v.Value)
+				}
+			}
+			info.Interfaces = c.interfaces()
+			return info, nil
+		}
+	}
+	return nil, lastErr
+}
+
+// snmpClient sends requests to one agent with one community
+type snmpClient struct {
+	conn      net.Conn
+	community string
+	version   int
+	timeout   time.Duration
+}
+
+// interfaces walks the ifTable one row at a time by asking for the next
+// value of each column together
+func (c *snmpClient) interfaces() []SNMPInterface {
+	columns := []int{2, 3, 4, 5, 6, 7, 8}
+	oids := make([]string, len(columns))
+	for i, col := range columns {
+		oids[i] = fmt.Sprintf("%s.%d", oidIfEntry, col)
+	}
+
+	var ifaces []SNMPInterface
+	for len(ifaces) < 1024 {
+		vars, err := c.request(snmpGetNext, oids)
+		if err != nil || len(vars) != len(columns) {
+			break
+		}
+
+		// The walk is over once the first column leaves the table
+		prefix := fmt.Sprintf("%s.%d.", oidIfEntry, columns[0])
+		if vars[0].Tag == berEndOfMib || !strings.HasPrefix(vars[0].OID, prefix) {
+			break
+		}
+
+		index, _ := strconv.Atoi(strings.TrimPrefix(vars[0].OID, prefix))
+		iface := SNMPInterface{Index: index}
+		for i, v := range vars {
+			if !strings.HasPrefix(v.OID, fmt.Sprintf("%s.%d.", oidIfEntry, columns[i])) {
+				continue
+			}
+			switch columns[i] {
+			case 2:
+				iface.Descr = string(v.Value)
+			case 3:
+				iface.Type = int(berUint(v.Value))
+			case 4:
+				iface.MTU = int(berUint(v.Value))
+			case 5:
+				iface.Speed = berUint(v.Value)
+			case 6:
+				if len(v.Value) > 0 {
+					iface.PhysAddress = net.HardwareAddr(v.Value)
+				}
+			case 7:
+				iface.AdminStatus = int(berUint(v.Value))
+			case 8:
+				iface.OperStatus = int(berUint(v.Value))
+			}
+			oids[i] = v.OID
+		}
+		ifaces = append(ifaces, iface)
+	}
+	return ifaces
+}
+
+// request sends a get or get-next for oids and returns the varbinds of the response
+func (c *snmpClient) request(pdu byte, oids []string) ([]snmpVarbind, error) {
+	var varbinds []byte
+	for _, o := range oids {
+		oid, err := berEncodeOID(o)
+		if err != nil {
+			return nil, err
+		}
+		varbinds = append(varbinds, berTLV(berSequence, append(berTLV(berOID, oid), berNull, 0))...)
+	}
+
+	id := rand.Int31()
+	body := berTLV(berInteger, berInt(int64(id)))
+	body = append(body, berTLV(berInteger, []byte{0})...)
+	body = append(body, berTLV(berInteger, []byte{0})...)
+	body = append(body, berTLV(berSequence, varbinds)...)
+
+	msg := berTLV(berInteger, berInt(int64(c.version)))
+	msg = append(msg, berTLV(berOctetString, []byte(c.community))...)
+	msg = append(msg, berTLV(pdu, body)...)
+	msg = berTLV(berSequence, msg)
+
+	c.conn.SetDeadline(time.Now().Add(c.timeout))
+	if _, err := c.conn.Write(msg); err != nil {
+		return nil, err
+	}
+
+	buff := make([]byte, 65535)
+	for {
+		n, err := c.conn.Read(buff)
+		if err != nil {
+			return nil, err
+		}
+		vars, respID, err := parseSNMPResponse(buff[:n])
+		if err != nil {
+			return nil, err
+		}
+		if respID == id {
+			return vars, nil
+		}
+	}
+}
+
+// parseSNMPResponse decodes a response message into its varbinds
+func parseSNMPResponse(b []byte) ([]snmpVarbind, int32, error) {
+	tag, msg, _, err := berRead(b)
+	if err != nil || tag != berSequence {
+		return nil, 0, errors.New("snmp: invalid message")
+	}
+
+	// Version and community are not checked, the request id is enough
+	var v []byte
+	for i := 0; i < 2; i++ {
+		if _, _, msg, err = berRead(msg); err != nil {
+			return nil, 0, err
+		}
+	}
+	if tag, v, _, err = berRead(msg); err != nil || tag != snmpResponse {
+		return nil, 0, errors.New("snmp: not a response")
+	}
+
+	var fields [4][]byte
+	for i := range fields {
+		if _, fields[i], v, err = berRead(v); err != nil {
+			return nil, 0, err
+		}
+	}
+	id := int32(berUint(fields[0]))
+	if status := berUint(fields[1]); status != 0 {
+		return nil, id, fmt.Errorf("snmp: error status %d", status)
+	}
+
+	var vars []snmpVarbind
+	for list := fields[3]; len(list) > 0; {
+		var vb []byte
+		if _, vb, list, err = berRead(list); err != nil {
+			return nil, id, err
+		}
+		_, oid, rest, err := berRead(vb)
+		if err != nil {
+			return nil, id, err
+		}
+		tag, value, _, err := berRead(rest)
+		if err != nil {
+			return nil, id, err
+		}
+		name, err := berDecodeOID(oid)
+		if err != nil {
+			return nil, id, err
+		}
+		vars = append(vars, snmpVarbind{OID: name, Tag: tag, Value: value})
+	}
+	return vars, id, nil
+}
+
+// berTLV encodes a tag, length and value
+func berTLV(tag byte, value []byte) []byte {
+	b := []byte{tag}
+	switch n := len(value); {
+	case n < 0x80:
+		b = append(b, byte(n))
+	case n <= 0xff:
+		b = append(b, 0x81, byte(n))
+	default:
+		b = append(b, 0x82, byte(n>>8), byte(n))
+	}
+	return append(b, value...)
+}
+
+// berRead splits the first tag, length and value from b
+func berRead(b []byte) (byte, []byte, []byte, error) {
+	if len(b) < 2 {
+		return 0, nil, nil, errors.New("snmp: truncated")
+	}
+	tag := b[0]
+	length := int(b[1])
+	off := 2
+	if length&0x80 != 0 {
+		n := length & 0x7f
+		if n == 0 || n > 3 || len(b) < 2+n {
+			return 0, nil, nil, errors.New("snmp: invalid length")
+		}
+		length = 0
+		for _, x := range b[2 : 2+n] {
+			length = length<<8 | int(x)
+		}
+		off += n
+	}
+	if len(b) < off+length {
+		return 0, nil, nil, errors.New("snmp: truncated")
+	}
+	return tag, b[off : off+length], b[off+length:], nil
+}
+
+// berInt encodes a two's complement integer in the fewest bytes
+func berInt(v int64) []byte {
+	b := []byte{byte(v)}
+	for v > 0x7f || v < -0x80 {
+		v >>= 8
+		b = append([]byte{byte(v)}, b...)
+	}
+	return b
+}
+
+// berUint decodes an unsigned integer such as a Counter32 or TimeTicks
+func berUint(b []byte) uint64 {
+	var v uint64
+	for _, x := range b {
+		v = v<<8 | uint64(x)
+	}
+	return v
+}
+
+// berEncodeOID encodes a dotted object identifier
+func berEncodeOID(oid string) ([]byte, error) {
+	parts := strings.Split(oid, ".")
+	if len(parts) < 2 {
+		return nil, fmt.Errorf("snmp: invalid oid %q", oid)
+	}
+	ids := make([]uint64, len(parts))
+	for i, p := range parts {
+		n, err := strconv.ParseUint(p, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("snmp: invalid oid %q", oid)
+		}
+		ids[i] = n
+	}
+
+	b := []byte{byte(ids[0]*40 + ids[1])}
+	for _, id := range ids[2:] {
+		var enc []byte
+		enc = append(enc, byte(id&0x7f))
+		for id >>= 7; id > 0; id >>= 7 {
+			enc = append([]byte{byte(id&0x7f) | 0x80}, enc...)
+		}
+		b = append(b, enc...)
+	}
+	return b, nil
+}
+
+// berDecodeOID decodes an object identifier to dotted form
+func berDecodeOID(b []byte) (string, error) {
+	if len(b) == 0 {
+		return "", errors.New("snmp: empty oid")
+	}
+	parts := []string{strconv.Itoa(int(b[0]) / 40), strconv.Itoa(int(b[0]) % 40)}
+	var id uint64
+	for _, x := range b[1:] {
+		id = id<<7 | uint64(x&0x7f)
+		if x&0x80 == 0 {
+			parts = append(parts, strconv.FormatUint(id, 10))
+			id = 0
+		}
+	}
+	if b[len(b)-1]&0x80 != 0 {
+		return "", errors.New("snmp: truncated oid")
+	}
+	return strings.Join(parts, "."), nil
+}
@@ -0,0 +1,14 @@
+//go:build !aix && !darwin && !dragonfly && !freebsd && !linux && !netbsd && !openbsd && !solaris && !windows
+// +build !aix,!darwin,!dragonfly,!freebsd,!linux,!netbsd,!openbsd,!solaris,!windows
+
+package gomap
+
+import (
+	"fmt"
+	"net"
+)
+
+// setTTL is not supported on this platform
+func setTTL(conn net.Conn, ttl int) error {
+	return fmt.Errorf("socket: setting ttl is not supported")
+}
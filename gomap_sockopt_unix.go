@@ -0,0 +1,36 @@
+//go:build aix || darwin || dragonfly || freebsd || linux || netbsd || openbsd || solaris
+// +build aix darwin dragonfly freebsd linux netbsd openbsd solaris
+
+package gomap
+
+import (
+	"fmt"
+	"net"
+	"syscall"
+)
+
+// setTTL sets the time to live of packets sent on conn
+func setTTL(conn net.Conn, ttl int) error {
+	return setSockopt(conn, syscall.IPPROTO_IP, syscall.IP_TTL, ttl)
+}
+
+// setSockopt sets an integer socket option on conn
+func setSockopt(conn net.Conn, level, opt, value int) error {
+	sc, ok := conn.(syscall.Conn)
+	if !ok {
+		return fmt.Errorf("socket: %T does not support socket options", conn)
+	}
+	raw, err := sc.SyscallConn()
+	if err != nil {
+		return err
+	}
+
+	var serr error
+	err = raw.Control(func(fd uintptr) {
+		serr = syscall.SetsockoptInt(int(fd), level, opt, value)
+	})
+	if err != nil {
+		return err
+	}
+	return serr
+}
@@ -0,0 +1,215 @@
+package gomap
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+// UPnPDevice is a device that answered an SSDP search
+type UPnPDevice struct {
+	IP       net.IP
+	Location string
+	Server   string
+	USN      string
+
+	// Read from the device description at Location
+	FriendlyName string
+	DeviceType   string
+	Manufacturer string
+	ModelName    string
+	ModelNumber  string
+	// Services are the service types of the device and its embedded devices
+	Services []string
+}
+
+// String with the name, model and services of the device
+func (d UPnPDevice) String() string {
+	name := d.FriendlyName
+	if name == "" {
+		name = d.Server
+	}
+	model := strings.TrimSpace(d.Manufacturer + " " + d.ModelName + " " + d.ModelNumber)
+	if model != "" {
+		name += " (" + model + ")"
+	}
+	if len(d.Services) > 0 {
+		name += " [" + strings.Join(d.Services, ", ") + "]"
+	}
+	return name
+}
+
+// ssdpAddr is the ipv4 SSDP group
+var ssdpAddr = &net.UDPAddr{IP: net.IPv4(239, 255, 255, 250), Port: 1900}
+
+// upnpDescription is the part of a device description that is kept
+type upnpDescription struct {
+	Device upnpDeviceXML `xml:"device"`
+}
+
+type upnpDeviceXML struct {
+	DeviceType   string          `xml:"deviceType"`
+	FriendlyName string          `xml:"friendlyName"`
+	Manufacturer string          `xml:"manufacturer"`
+	ModelName    string          `xml:"modelName"`
+	ModelNumber  string          `xml:"modelNumber"`
+	Services     []string        `xml:"serviceList>service>serviceType"`
+	Devices      []upnpDeviceXML `xml:"deviceList>device"`
+}
+
+// DiscoverSSDP searches for UPnP devices on the local network and reads
+// their device descriptions, waiting up to timeout for answers
+func DiscoverSSDP(timeout time.Duration) ([]UPnPDevice, error) {
+	return discoverSSDP("", &Scope{AllowPublic: true}, timeout)
+}
+
+// discoverSSDP sends an M-SEARCH from laddr and collects the unicast
+// replies of devices in scope, the others are not contacted again
+func discoverSSDP(laddr string, scope *Scope, timeout time.Duration) ([]UPnPDevice, error) {
+	conn, err := net.ListenUDP("udp4", &net.UDPAddr{IP: net.ParseIP(laddr)})
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	mx := int(timeout / time.Second)
+	if mx < 1 {
+		mx = 1
+	}
+	search := fmt.Sprintf("M-SEARCH * HTTP/1.1\r\nHOST: %s\r\nMAN: \"ssdp:discover\"\r\nMX: %d\r\nST: ssdp:all\r\n\r\n", ssdpAddr, mx)
+	if _, err := conn.WriteTo([]byte(search), ssdpAddr); err != nil {
+		return nil, err
+	}
+
+	// Devices answer once per service type so replies are grouped by location
+	devices := make(map[string]*UPnPDevice)
+	conn.SetReadDeadline(time.Now().Add(timeout))
+	buff := make([]byte, 4096)
+	for {
+		n, addr, err := conn.ReadFrom(buff)
+		if err != nil {
+			if ne, ok := err.(net.Error); ok && ne.Timeout() {
+				break
+			}
+			return nil, err
+		}
+
+		ip := addr.(*net.UDPAddr).IP
+		d, ok := parseSSDPReply(buff[:n], ip)
+		if !ok || devices[d.Location] != nil || !scope.allows(ip.String()) {
+			continue
+		}
+		devices[d.Location] = d
+	}
+
+	client := upnpClient(timeout)
+	var found []UPnPDevice
+	for _, d := range devices {
+		describeUPnP(client, d)
+		found = append(found, *d)
+	}
+	sort.Slice(found, func(i, j int) bool {
+		return found[i].Location < found[j].Location
+	})
+	return found, nil
+}
+
+// upnpClient fetches descriptions without following redirects so they
+// are only read from the device that answered
+func upnpClient(timeout time.Duration) *http.Client {
+	return &http.Client{
+		Timeout: timeout,
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+// parseSSDPReply returns the device announced by a reply to an M-SEARCH from ip
+func parseSSDPReply(b []byte, ip net.IP) (*UPnPDevice, bool) {
+	resp, err := http.ReadResponse(bufio.NewReader(bytes.NewReader(b)), nil)
+	if err != nil || resp.StatusCode != http.StatusOK {
+		return nil, false
+	}
+	location := resp.Header.Get("Location")
+	if location == "" {
+		return nil, false
+	}
+	return &UPnPDevice{
+		IP:       ip,
+		Location: location,
+		Server:   resp.Header.Get("Server"),
+		USN:      resp.Header.Get("Usn"),
+	}, true
+}
+
+// describeUPnP fills in d from its device description. Descriptions are
+// only fetched from the device itself so a reply cannot point the scanner
+// at another host.
+func describeUPnP(client *http.Client, d *UPnPDevice) {
+	u, err := url.Parse(d.Location)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || !net.ParseIP(u.Hostname()).Equal(d.IP) {
+		return
+	}
+
+	resp, err := client.Get(d.Location)
+	if err != nil {
+		return
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return
+	}
+
+	var desc upnpDescription
+	if err := xml.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&desc); err != nil {
+		return
+	}
+
+	dev := desc.Device
+	d.FriendlyName = strings.TrimSpace(dev.FriendlyName)
+	d.DeviceType = strings.TrimSpace(dev.DeviceType)
+	d.Manufacturer = strings.TrimSpace(dev.Manufacturer)
+	d.ModelName = strings.TrimSpace(dev.ModelName)
+	d.ModelNumber = strings.TrimSpace(dev.ModelNumber)
+	d.Services = upnpServices(dev, nil)
+}
+
+// upnpServices lists the service types of dev and its embedded devices
+func upnpServices(dev upnpDeviceXML, services []string) []string {
+	for _, s := range dev.Services {
+		if s = strings.TrimSpace(s); s != "" && !containsString(services, s) {
+			services = append(services, s)
+		}
+	}
+	for _, child := range dev.Devices {
+		services = upnpServices(child, services)
+	}
+	return services
+}
+
+// upnpHosts groups devices by address
+func upnpHosts(devices []UPnPDevice) map[string][]UPnPDevice {
+	hosts := make(map[string][]UPnPDevice)
+	for _, d := range devices {
+		hosts[d.IP.String()] = append(hosts[d.IP.String()], d)
+	}
+	return hosts
+}
+
+// addUPnP attaches the discovered devices to the results of their hosts
+func addUPnP(results RangeScanResult, hosts map[string][]UPnPDevice) {
+	for _, r := range results {
+		if ip := r.address(); ip != nil {
+			r.UPnP = hosts[ip.String()]
+		}
+	}
+}
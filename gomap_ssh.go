@@ -0,0 +1,359 @@
+package gomap
+
+import (
+	"bufio"
+	"crypto/ecdh"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net"
+	"strings"
+	"time"
+)
+
+// SSHInfo is what an SSH server reveals before authentication
+type SSHInfo struct {
+	Banner            string
+	KexAlgorithms     []string
+	HostKeyAlgorithms []string
+	Ciphers           []string
+	MACs              []string
+	Compression       []string
+	HostKeys          []SSHHostKey
+	// Deprecated lists the offered algorithms that are considered weak
+	Deprecated []string
+}
+
+// SSHHostKey is a host key of an SSH server
+type SSHHostKey struct {
+	Type string
+	// Fingerprint is the base64 SHA256 hash in the format used by ssh-keygen
+	Fingerprint string
+}
+
+// String with the type and fingerprint of the key
+func (k SSHHostKey) String() string {
+	return k.Type + " " + k.Fingerprint
+}
+
+// SSH message numbers used during the key exchange
+const (
+	sshMsgKexInit   = 20
+	sshMsgKexDHInit = 30
+	sshMsgKexReply  = 31
+)
+
+// sshKexAlgorithms are the key exchange methods the probe can perform
+var sshKexAlgorithms = []string{
+	"curve25519-sha256",
+	"curve25519-sha256@libssh.org",
+	"ecdh-sha2-nistp256",
+	"diffie-hellman-group14-sha256",
+	"diffie-hellman-group14-sha1",
+}
+
+// sshGroup14 is the 2048 bit MODP group of RFC 3526
+var sshGroup14, _ = new(big.Int).SetString("FFFFFFFFFFFFFFFFC90FDAA22168C234C4C6628B80DC1CD129024E088A67CC74020BBEA63B139B22514A08798E3404DDEF9519B3CD3A431B302B0A6DF25F14374FE1356D6D51C245E485B576625E7EC6F44C42E9A637ED6B0BFF5CB6F406B7EDEE386BFB5A899FA5AE9F24117C4B1FE649286651ECE45B3DC2007CB8A163BF0598DA48361C55D39A69163FA8FD24CF5F83655D23DCA3AD961C62F356208552BB9ED529077096966D670C354E4ABC9804F1746C08CA18217C32905E462E36CE3BE39E772C180E86039B2783A2EC07A28FB5C55DF06F4C52C9DE2BCBF6955817183995497CEA956AE515D2261898FA051015728E5A8AACAA68FFFFFFFFFFFFFFFF", 16)
+
+// sshDeprecated are algorithms that should no longer be offered
+var sshDeprecated = map[string]bool{
+	"diffie-hellman-group1-sha1":         true,
+	"diffie-hellman-group14-sha1":        true,
+	"diffie-hellman-group-exchange-sha1": true,
+	"ssh-dss":                            true,
+	"ssh-rsa":                            true,
+	"ssh-rsa-cert-v01@openssh.com":       true,
+	"ssh-dss-cert-v01@openssh.com":       true,
+	"3des-cbc":                           true,
+	"aes128-cbc":                         true,
+	"aes192-cbc":                         true,
+	"aes256-cbc":                         true,
+	"blowfish-cbc":                       true,
+	"cast128-cbc":                        true,
+	"arcfour":                            true,
+	"arcfour128":                         true,
+	"arcfour256":                         true,
+	"rijndael-cbc@lysator.liu.se":        true,
+	"hmac-md5":                           true,
+	"hmac-md5-96":                        true,
+	"hmac-md5-etm@openssh.com":           true,
+	"hmac-md5-96-etm@openssh.com":        true,
+	"hmac-sha1-96":                       true,
+	"hmac-sha1-96-etm@openssh.com":       true,
+	"hmac-ripemd160":                     true,
+	"hmac-ripemd160@openssh.com":         true,
+	"hmac-ripemd160-etm@openssh.com":     true,
+	"umac-64@openssh.com":                true,
+	"umac-64-etm@openssh.com":            true,
+}
+
+// InspectSSH connects to an SSH server and records its banner, offered
+// algorithms and host key fingerprints without authenticating
+func InspectSSH(hostname string, port int) (*SSHInfo, error) {
+	return inspectSSH(net.JoinHostPort(hostname, fmt.Sprint(port)), 5*time.Second)
+}
+
+// inspectSSH reads the algorithms from a first connection then makes one
+// more connection for each host key type to get its fingerprint
+func inspectSSH(address string, timeout time.Duration) (*SSHInfo, error) {
+	info, _, err := sshHandshake(address, "", "", timeout)
+	if err != nil {
+		return nil, err
+	}
+
+	kex := ""
+	for _, k := range sshKexAlgorithms {
+		if containsString(info.KexAlgorithms, k) {
+			kex = k
+			break
+		}
+	}
+
+	// Only one key of each type is fetched, rsa-sha2 and ssh-rsa share a key
+	if kex != "" {
+		seen := make(map[string]bool)
+		for _, alg := range info.HostKeyAlgorithms {
+			if strings.Contains(alg, "-cert-") {
+				continue
+			}
+			_, key, err := sshHandshake(address, kex, alg, timeout)
+			if err != nil || key == nil || seen[key.Fingerprint] {
+				continue
+			}
+			seen[key.Fingerprint] = true
+			info.HostKeys = append(info.HostKeys, *key)
+		}
+	}
+
+	for _, list := range [][]string{info.KexAlgorithms, info.HostKeyAlgorithms, info.Ciphers, info.MACs} {
+		for _, alg := range list {
+			if sshDeprecated[alg] || strings.HasPrefix(alg, "gss-gex-sha1-") || strings.HasPrefix(alg, "gss-group1-sha1-") {
+				info.Deprecated = append(info.Deprecated, alg)
+			}
+		}
+	}
+	if strings.HasPrefix(info.Banner, "SSH-1.") {
+		info.Deprecated = append(info.Deprecated, "protocol 1")
+	}
+	return info, nil
+}
+
+// sshHandshake exchanges versions and KEXINIT messages with the server.
+// When kex and hostKey are set it continues the key exchange far enough
+// to receive the host key of that type.
+func sshHandshake(address, kex, hostKey string, timeout time.Duration) (*SSHInfo, *SSHHostKey, error) {
+	conn, err := net.DialTimeout("tcp", address, timeout)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(timeout))
+
+	if _, err := conn.Write([]byte("SSH-2.0-gomap\r\n")); err != nil {
+		return nil, nil, err
+	}
+
+	// Servers may send other lines before the version
+	r := bufio.NewReader(conn)
+	info := &SSHInfo{}
+	for i := 0; ; i++ {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return nil, nil, err
+		}
+		if strings.HasPrefix(line, "SSH-") {
+			info.Banner = strings.TrimRight(line, "\r\n")
+			break
+		}
+		if i > 32 {
+			return nil, nil, errors.New("ssh: no version banner")
+		}
+	}
+	if strings.HasPrefix(info.Banner, "SSH-1.") && !strings.HasPrefix(info.Banner, "SSH-1.99") {
+		return info, nil, nil
+	}
+
+	payload, err := readSSHPacket(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	lists, err := parseKexInit(payload)
+	if err != nil {
+		return nil, nil, err
+	}
+	info.KexAlgorithms = lists[0]
+	info.HostKeyAlgorithms = lists[1]
+	info.Ciphers = lists[2]
+	info.MACs = lists[4]
+	info.Compression = lists[6]
+
+	if kex == "" {
+		return info, nil, nil
+	}
+
+	// Offer exactly what is wanted and echo the server's choice for the rest
+	first := func(l []string) string {
+		if len(l) == 0 {
+			return "none"
+		}
+		return l[0]
+	}
+	init := []byte{sshMsgKexInit}
+	cookie := make([]byte, 16)
+	rand.Read(cookie)
+	init = append(init, cookie...)
+	for _, l := range []string{kex, hostKey, first(lists[2]), first(lists[3]), first(lists[4]), first(lists[5]), "none", "none", "", ""} {
+		init = appendSSHString(init, []byte(l))
+	}
+	init = append(init, 0, 0, 0, 0, 0)
+	if err := writeSSHPacket(conn, init); err != nil {
+		return nil, nil, err
+	}
+
+	pub, err := sshKexPublic(kex)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := writeSSHPacket(conn, append([]byte{sshMsgKexDHInit}, pub...)); err != nil {
+		return nil, nil, err
+	}
+
+	// Skip anything sent before the reply such as SSH_MSG_IGNORE
+	for {
+		payload, err := readSSHPacket(r)
+		if err != nil {
+			return nil, nil, err
+		}
+		if len(payload) == 0 || payload[0] != sshMsgKexReply {
+			continue
+		}
+
+		blob, _, err := sshString(payload[1:])
+		if err != nil {
+			return nil, nil, err
+		}
+		keyType, _, err := sshString(blob)
+		if err != nil {
+			return nil, nil, err
+		}
+		sum := sha256.Sum256(blob)
+		return info, &SSHHostKey{
+			Type:        string(keyType),
+			Fingerprint: "SHA256:" + base64.RawStdEncoding.EncodeToString(sum[:]),
+		}, nil
+	}
+}
+
+// parseKexInit returns the ten name-lists of a KEXINIT message, client to
+// server and server to client lists alternating after the first two
+func parseKexInit(payload []byte) ([][]string, error) {
+	if len(payload) < 17 || payload[0] != sshMsgKexInit {
+		return nil, errors.New("ssh: expected KEXINIT")
+	}
+
+	lists := make([][]string, 10)
+	rest := payload[17:]
+	for i := range lists {
+		s, r, err := sshString(rest)
+		if err != nil {
+			return nil, err
+		}
+		if len(s) > 0 {
+			lists[i] = strings.Split(string(s), ",")
+		}
+		rest = r
+	}
+	return lists, nil
+}
+
+// sshKexPublic returns the encoded ephemeral public key sent in the
+// KEX_ECDH_INIT or KEXDH_INIT message of kex. The private key is dropped
+// as the exchange is never finished.
+func sshKexPublic(kex string) ([]byte, error) {
+	var curve ecdh.Curve
+	switch kex {
+	case "curve25519-sha256", "curve25519-sha256@libssh.org":
+		curve = ecdh.X25519()
+	case "ecdh-sha2-nistp256":
+		curve = ecdh.P256()
+	default:
+		x, err := rand.Int(rand.Reader, new(big.Int).Sub(sshGroup14, big.NewInt(2)))
+		if err != nil {
+			return nil, err
+		}
+		x.Add(x, big.NewInt(1))
+		return appendSSHMpint(nil, new(big.Int).Exp(big.NewInt(2), x, sshGroup14)), nil
+	}
+
+	priv, err := curve.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	return appendSSHString(nil, priv.PublicKey().Bytes()), nil
+}
+
+// readSSHPacket reads an unencrypted binary packet and returns its payload
+func readSSHPacket(r io.Reader) ([]byte, error) {
+	var header [5]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		return nil, err
+	}
+	length := binary.BigEndian.Uint32(header[0:4])
+	padding := uint32(header[4])
+	if length < padding+1 || length > 256*1024 {
+		return nil, errors.New("ssh: invalid packet length")
+	}
+
+	body := make([]byte, length-1)
+	if _, err := io.ReadFull(r, body); err != nil {
+		return nil, err
+	}
+	return body[:length-1-padding], nil
+}
+
+// writeSSHPacket pads payload to a multiple of 8 bytes and writes it
+func writeSSHPacket(w io.Writer, payload []byte) error {
+	padding := 8 - (5+len(payload))%8
+	if padding < 4 {
+		padding += 8
+	}
+
+	b := make([]byte, 5, 5+len(payload)+padding)
+	binary.BigEndian.PutUint32(b[0:4], uint32(1+len(payload)+padding))
+	b[4] = byte(padding)
+	b = append(b, payload...)
+	b = append(b, make([]byte, padding)...)
+	_, err := w.Write(b)
+	return err
+}
+
+// sshString splits a length prefixed string from the front of b
+func sshString(b []byte) ([]byte, []byte, error) {
+	if len(b) < 4 {
+		return nil, nil, errors.New("ssh: short message")
+	}
+	n := binary.BigEndian.Uint32(b[0:4])
+	if uint32(len(b)-4) < n {
+		return nil, nil, errors.New("ssh: short message")
+	}
+	return b[4 : 4+n], b[4+n:], nil
+}
+
+func appendSSHString(b []byte, s []byte) []byte {
+	b = append(b, byte(len(s)>>24), byte(len(s)>>16), byte(len(s)>>8), byte(len(s)))
+	return append(b, s...)
+}
+
+// appendSSHMpint appends a positive integer in two's complement form
+func appendSSHMpint(b []byte, n *big.Int) []byte {
+	v := n.Bytes()
+	if len(v) > 0 && v[0]&0x80 != 0 {
+		v = append([]byte{0}, v...)
+	}
+	return appendSSHString(b, v)
+}
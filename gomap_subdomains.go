@@ -0,0 +1,355 @@
+package gomap
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math/rand"
+	"net"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/JustinTimperio/gomap/packet"
+)
+
+// Subdomain is a name found under a domain and the addresses it resolves to
+type Subdomain struct {
+	Name string
+	IPs  []net.IP
+	// Source is "wordlist" or "axfr"
+	Source string
+}
+
+// String with the name and its addresses
+func (s Subdomain) String() string {
+	var ips []string
+	for _, ip := range s.IPs {
+		ips = append(ips, ip.String())
+	}
+	return fmt.Sprintf("%s (%s)", s.Name, strings.Join(ips, ", "))
+}
+
+// SubdomainOptions contains the settings used to enumerate subdomains
+type SubdomainOptions struct {
+	// Wordlist holds labels tried under the domain. WordlistFile is
+	// read for more, one label per line with # starting a comment.
+	Wordlist     []string
+	WordlistFile string
+
+	// Workers is the number of concurrent lookups, defaults to 50
+	Workers int
+
+	// ZoneTransfer attempts an AXFR from each authoritative server.
+	// Only enable this against domains you are permitted to test.
+	ZoneTransfer bool
+
+	// Resolver is used for every lookup, the system resolver if nil
+	Resolver *Resolver
+
+	// Scope refuses enumerating a domain it denies
+	Scope *Scope
+}
+
+// LoadWordlist reads a subdomain wordlist file
+func LoadWordlist(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var words []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if i := strings.IndexByte(line, '#'); i >= 0 {
+			line = line[:i]
+		}
+		if line = strings.TrimSpace(line); line != "" {
+			words = append(words, line)
+		}
+	}
+	return words, scanner.Err()
+}
+
+// EnumerateSubdomains finds names under domain by looking up each word
+// of the wordlist and, if enabled, transferring the zone
+func EnumerateSubdomains(domain string, opts SubdomainOptions) ([]Subdomain, error) {
+	domain = strings.ToLower(strings.TrimSuffix(domain, "."))
+	if err := opts.Scope.CheckTarget(domain); err != nil {
+		return nil, err
+	}
+	if opts.Resolver == nil {
+		opts.Resolver = NewResolver()
+	}
+	if opts.Workers <= 0 {
+		opts.Workers = 50
+	}
+
+	words := opts.Wordlist
+	if opts.WordlistFile != "" {
+		w, err := LoadWordlist(opts.WordlistFile)
+		if err != nil {
+			return nil, err
+		}
+		words = append(words, w...)
+	}
+
+	found := make(map[string]*Subdomain)
+	if opts.ZoneTransfer {
+		for _, s := range transferZone(domain, opts.Resolver) {
+			found[s.Name] = &Subdomain{Name: s.Name, IPs: s.IPs, Source: s.Source}
+		}
+	}
+
+	// Wildcard records answer for any label so their addresses are ignored
+	wildcard, _ := opts.Resolver.LookupIP(fmt.Sprintf("gomap-%08x.%s", rand.Uint32(), domain))
+
+	names := []string{domain}
+	for _, w := range words {
+		names = append(names, strings.ToLower(strings.Trim(w, "."))+"."+domain)
+	}
+
+	var mu sync.Mutex
+	in := make(chan string)
+	var wg sync.WaitGroup
+	for i := 0; i < opts.Workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for name := range in {
+				ips, err := opts.Resolver.LookupIP(name)
+				if err != nil || len(ips) == 0 || (len(wildcard) > 0 && containsAll(wildcard, ips)) {
+					continue
+				}
+
+				mu.Lock()
+				if _, ok := found[name]; !ok {
+					found[name] = &Subdomain{Name: name, IPs: ips, Source: "wordlist"}
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+	for _, n := range names {
+		in <- n
+	}
+	close(in)
+	wg.Wait()
+
+	var subs []Subdomain
+	for _, s := range found {
+		subs = append(subs, *s)
+	}
+	sort.Slice(subs, func(i, j int) bool {
+		return subs[i].Name < subs[j].Name
+	})
+	return subs, nil
+}
+
+// containsAll reports whether every address of ips is in set
+func containsAll(set []net.IP, ips []net.IP) bool {
+	for _, ip := range ips {
+		found := false
+		for _, s := range set {
+			if s.Equal(ip) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// transferZone tries a zone transfer from each name server of domain
+// and returns the names of the first one that allows it
+func transferZone(domain string, resolver *Resolver) []Subdomain {
+	servers, err := resolver.LookupNS(domain)
+	if err != nil {
+		return nil
+	}
+
+	for _, ns := range servers {
+		ips, err := resolver.LookupIP(strings.TrimSuffix(ns, "."))
+		if err != nil {
+			continue
+		}
+		for _, ip := range ips {
+			records, err := axfr(domain, net.JoinHostPort(ip.String(), "53"), 10*time.Second)
+			if err == nil {
+				return zoneSubdomains(domain, records, resolver)
+			}
+		}
+	}
+	return nil
+}
+
+// zoneSubdomains turns the records of a transferred zone into subdomains.
+// CNAMEs are resolved as their targets are often outside the zone.
+func zoneSubdomains(domain string, records []packet.DNSResource, resolver *Resolver) []Subdomain {
+	addrs := make(map[string][]net.IP)
+	var order []string
+	for _, r := range records {
+		name := strings.ToLower(strings.TrimSuffix(r.Name, "."))
+		if name != domain && !strings.HasSuffix(name, "."+domain) {
+			continue
+		}
+
+		switch r.Type {
+		case packet.DNSTypeA, packet.DNSTypeAAAA, packet.DNSTypeCNAME:
+			if _, ok := addrs[name]; !ok {
+				order = append(order, name)
+				addrs[name] = nil
+			}
+			if r.IP != nil {
+				addrs[name] = append(addrs[name], r.IP)
+			}
+		}
+	}
+
+	var subs []Subdomain
+	for _, name := range order {
+		ips := addrs[name]
+		if len(ips) == 0 {
+			ips, _ = resolver.LookupIP(name)
+		}
+		if len(ips) > 0 {
+			subs = append(subs, Subdomain{Name: name, IPs: ips, Source: "axfr"})
+		}
+	}
+	return subs
+}
+
+// axfr transfers the zone of domain from server over tcp
+func axfr(domain string, server string, timeout time.Duration) ([]packet.DNSResource, error) {
+	conn, err := net.DialTimeout("tcp", server, timeout)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(timeout))
+
+	query := packet.DNS{
+		ID:        uint16(rand.Uint32()),
+		Questions: []packet.DNSQuestion{{Name: domain, Type: packet.DNSTypeAXFR, Class: packet.DNSClassINET}},
+	}
+	msg, err := query.Marshal()
+	if err != nil {
+		return nil, err
+	}
+
+	// Messages over tcp are prefixed with their length
+	if _, err := conn.Write(append([]byte{byte(len(msg) >> 8), byte(len(msg))}, msg...)); err != nil {
+		return nil, err
+	}
+
+	// The zone starts and ends with its SOA record
+	var records []packet.DNSResource
+	soa := 0
+	for soa < 2 {
+		var length [2]byte
+		if _, err := io.ReadFull(conn, length[:]); err != nil {
+			return nil, err
+		}
+		buff := make([]byte, binary.BigEndian.Uint16(length[:]))
+		if _, err := io.ReadFull(conn, buff); err != nil {
+			return nil, err
+		}
+
+		resp, err := packet.ParseDNS(buff)
+		if err != nil {
+			return nil, err
+		}
+		if resp.ID != query.ID {
+			continue
+		}
+		if resp.Rcode() != 0 || len(resp.Answers) == 0 {
+			return nil, fmt.Errorf("zone transfer of %s refused by %s", domain, server)
+		}
+
+		for _, r := range resp.Answers {
+			if r.Type == packet.DNSTypeSOA {
+				soa++
+			}
+			records = append(records, r)
+		}
+	}
+	return records, nil
+}
+
+// ScanSubdomains scans every unique address of subs once. Each result
+// lists the names that resolved to its address in Domains.
+func ScanSubdomains(subs []Subdomain, opts ScanOptions) (RangeScanResult, error) {
+	laddr, err := prepareScan(&opts)
+	if err != nil {
+		return nil, err
+	}
+
+	// Addresses are only scanned for the names that may reach them, the
+	// others are skipped and reported once the scan is done
+	domains := make(map[string][]string)
+	var hosts []string
+	var scopeErr error
+	for _, s := range subs {
+		for _, ip := range s.IPs {
+			if opts.Stealth && ip.To4() == nil {
+				continue
+			}
+			if err := opts.Scope.CheckAddress(s.Name, ip); err != nil {
+				if scopeErr == nil {
+					scopeErr = err
+				}
+				continue
+			}
+			key := ip.String()
+			if _, ok := domains[key]; !ok {
+				hosts = append(hosts, key)
+			}
+			domains[key] = append(domains[key], s.Name)
+		}
+	}
+
+	opts.Scope = opts.Scope.withAddresses(hosts)
+
+	a, err := startAudit(&opts, "start", hosts)
+	if err != nil {
+		return nil, err
+	}
+
+	// Fastscan skips addresses without names, the subdomains name them
+	// even without a PTR record
+	opts.Resolver.addHostNames(domains)
+
+	cp := newCheckpoint(opts, hosts)
+	if cp != nil {
+		cp.Domains = domains
+	}
+	results, err := scanHosts(hosts, laddr, &opts, cp)
+	addDomains(results, domains)
+	if err == nil {
+		err = scopeErr
+	}
+	return results, a.finish(results, err)
+}
+
+// addDomains sets the Domains of each result from the address to name
+// mapping, naming hosts without a reverse record after their first domain
+func addDomains(results RangeScanResult, domains map[string][]string) {
+	for _, r := range results {
+		ip := r.address()
+		if ip == nil {
+			continue
+		}
+		r.Domains = domains[ip.String()]
+		if r.Hostname == "Unknown" && len(r.Domains) > 0 {
+			r.Hostname = r.Domains[0]
+		}
+	}
+}
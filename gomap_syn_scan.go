@@ -1,150 +1,261 @@
 package gomap
 
 import (
-	"bytes"
 	"encoding/binary"
+	"fmt"
 	"math/rand"
 	"net"
-	"strconv"
-	"strings"
+	"time"
+
+	"github.com/JustinTimperio/gomap/packet"
 )
 
-func sendSyn(laddr string, raddr string, sport uint16, dport uint16) error {
-	// Create TCP packet struct and header
-	op := []tcpOption{
-		{
-			Kind:   2,
-			Length: 4,
-			Data:   []byte{0x05, 0xb4},
-		},
-		{
-			Kind: 0,
-		},
-	}
-
-	tcpH := tcpHeader{
-		SrcPort:       sport,
-		DstPort:       dport,
-		SeqNum:        rand.Uint32(),
-		AckNum:        0,
-		Flags:         0x8002,
-		Window:        8192,
-		ChkSum:        0,
-		UrgentPointer: 0,
+// tcpResponse is the reply received for a raw TCP probe
+type tcpResponse struct {
+	Flags       packet.TCPFlags
+	Window      uint16
+	Unreachable bool
+}
+
+// probeFlags returns the TCP flags sent for each raw scan type
+func probeFlags(scan ScanType) packet.TCPFlags {
+	switch scan {
+	case ScanFIN:
+		return packet.TCPFin
+	case ScanNULL:
+		return 0
+	case ScanXmas:
+		return packet.TCPFin | packet.TCPPsh | packet.TCPUrg
+	case ScanACK, ScanWindow:
+		return packet.TCPAck
+	default:
+		return packet.TCPSyn
+	}
+}
+
+// classifyResponse turns the reply to a probe into a port state.
+// resp is nil if nothing was received before the timeout.
+func classifyResponse(scan ScanType, resp *tcpResponse) PortState {
+	if resp != nil && resp.Unreachable {
+		return PortFiltered
 	}
 
-	// Connect to network interface to send packet
-	conn, err := net.Dial("ip4:tcp", raddr)
+	switch scan {
+	case ScanFIN, ScanNULL, ScanXmas:
+		if resp == nil {
+			return PortOpenOrFiltered
+		}
+		if resp.Flags&packet.TCPRst != 0 {
+			return PortClosed
+		}
+	case ScanACK:
+		if resp != nil && resp.Flags&packet.TCPRst != 0 {
+			return PortUnfiltered
+		}
+	case ScanWindow:
+		if resp != nil && resp.Flags&packet.TCPRst != 0 {
+			if resp.Window > 0 {
+				return PortOpen
+			}
+			return PortClosed
+		}
+	default:
+		if resp == nil {
+			return PortFiltered
+		}
+		if resp.Flags&(packet.TCPSyn|packet.TCPAck) == packet.TCPSyn|packet.TCPAck {
+			return PortOpen
+		}
+		if resp.Flags&packet.TCPRst != 0 {
+			return PortClosed
+		}
+	}
+	return PortFiltered
+}
+
+// probeTCP sends a single packet with flags to raddr:dport and waits for the reply
+func probeTCP(laddr string, raddr string, dport uint16, flags packet.TCPFlags, timeout time.Duration) (*tcpResponse, error) {
+	dst, err := net.ResolveIPAddr("ip4", raddr)
 	if err != nil {
-		return err
+		return nil, err
+	}
+	listenAddr, err := net.ResolveIPAddr("ip4", laddr)
+	if err != nil {
+		return nil, err
 	}
-	defer conn.Close()
 
-	// Build dummy packet for checksum
-	buff := new(bytes.Buffer)
-	binary.Write(buff, binary.BigEndian, tcpH)
+	// Listeners are opened before sending so a fast reply is not missed
+	tcpConn, err := net.ListenIP("ip4:tcp", listenAddr)
+	if err != nil {
+		return nil, err
+	}
+	defer tcpConn.Close()
 
-	for i := range op {
-		binary.Write(buff, binary.BigEndian, op[i].Kind)
-		binary.Write(buff, binary.BigEndian, op[i].Length)
-		binary.Write(buff, binary.BigEndian, op[i].Data)
+	// ICMP errors are only used to mark ports as filtered
+	// so failing to listen for them is not fatal
+	icmpConn, err := net.ListenIP("ip4:icmp", listenAddr)
+	if err == nil {
+		defer icmpConn.Close()
 	}
 
-	binary.Write(buff, binary.BigEndian, [6]byte{})
-	data := buff.Bytes()
-	checkSum := checkSum(data, ipstr2Bytes(laddr), ipstr2Bytes(raddr))
-	tcpH.ChkSum = checkSum
+	deadline := time.Now().Add(timeout)
+	sport := uint16(random(10000, 65535))
+	res := make(chan *tcpResponse, 2)
 
-	// Build final packet
-	buff = new(bytes.Buffer)
-	binary.Write(buff, binary.BigEndian, tcpH)
+	go recvTCP(tcpConn, dst.IP, sport, dport, deadline, res)
+	if icmpConn != nil {
+		go recvICMP(icmpConn, dst.IP, sport, dport, deadline, res)
+	}
 
-	for i := range op {
-		binary.Write(buff, binary.BigEndian, op[i].Kind)
-		binary.Write(buff, binary.BigEndian, op[i].Length)
-		binary.Write(buff, binary.BigEndian, op[i].Data)
+	if err := sendTCP(laddr, dst.IP.String(), sport, dport, flags); err != nil {
+		return nil, err
 	}
-	binary.Write(buff, binary.BigEndian, [6]byte{})
 
-	// Send Packet
-	conn.Write(buff.Bytes())
-	return nil
+	select {
+	case r := <-res:
+		return r, nil
+	case <-time.After(time.Until(deadline)):
+		return nil, nil
+	}
 }
 
-func recvSynAck(laddr string, raddr string, port uint16, res chan<- bool) error {
-	// Checks if the IP address is resolveable
-	listenAddr, err := net.ResolveIPAddr("ip4", laddr)
+// sendTCP sends a TCP packet with the provided flags from laddr to raddr
+func sendTCP(laddr string, raddr string, sport uint16, dport uint16, flags packet.TCPFlags) error {
+	tcp := packet.TCP{
+		SrcPort: sport,
+		DstPort: dport,
+		Seq:     rand.Uint32(),
+		Flags:   flags,
+		Window:  8192,
+	}
+	if flags&packet.TCPAck != 0 {
+		tcp.Ack = rand.Uint32()
+	}
+	// Only SYN packets advertise a MSS to look like a normal connection
+	if flags&packet.TCPSyn != 0 {
+		tcp.Options = []packet.TCPOption{packet.MSSOption(1460)}
+	}
+
+	src, dst := net.ParseIP(laddr), net.ParseIP(raddr)
+	data, err := tcp.Marshal(src, dst)
 	if err != nil {
 		return err
 	}
+	return sendRaw(src, dst, "tcp", data)
+}
+
+// rawConn opens a raw socket for proto from src to dst. Binding to src
+// keeps the address the kernel sends from the same as the one used in
+// checksums and listened on for replies.
+func rawConn(src net.IP, dst net.IP, proto string) (*net.IPConn, error) {
+	return net.DialIP("ip4:"+proto, &net.IPAddr{IP: src}, &net.IPAddr{IP: dst})
+}
 
-	// Connect to network interface to listen for packets
-	conn, err := net.ListenIP("ip4:tcp", listenAddr)
+// sendRaw sends a packet built by the packet package from src to dst over proto
+func sendRaw(src net.IP, dst net.IP, proto string, data []byte) error {
+	// Connect to network interface to send packet
+	conn, err := rawConn(src, dst, proto)
 	if err != nil {
 		return err
 	}
 	defer conn.Close()
 
-	// Read each packet looking for ack from raddr on packetport
+	// Send Packet
+	_, err = conn.Write(data)
+	return err
+}
+
+// recvTCP waits for the TCP reply from raddr:dport to a probe sent from sport
+func recvTCP(conn *net.IPConn, raddr net.IP, sport uint16, dport uint16, deadline time.Time, res chan<- *tcpResponse) {
+	conn.SetReadDeadline(deadline)
+
+	// Read each packet looking for a reply from raddr on dport
+	buff := make([]byte, 1500)
 	for {
-		buff := make([]byte, 1024)
-		_, addr, err := conn.ReadFrom(buff)
+		n, addr, err := conn.ReadFrom(buff)
 		if err != nil {
+			if ne, ok := err.(net.Error); ok && ne.Timeout() {
+				return
+			}
 			continue
 		}
-		if addr.String() != raddr || buff[13] != 0x12 {
+		if !addr.(*net.IPAddr).IP.Equal(raddr) {
 			continue
 		}
 
-		var packetport uint16
-		binary.Read(bytes.NewReader(buff), binary.BigEndian, &packetport)
-		if port != packetport {
+		tcp, err := packet.ParseTCP(buff[:n])
+		if err != nil || tcp.SrcPort != dport || tcp.DstPort != sport {
 			continue
 		}
 
-		res <- true
-		return nil
+		res <- &tcpResponse{
+			Flags:  tcp.Flags,
+			Window: tcp.Window,
+		}
+		return
 	}
 }
 
-func checkSum(data []byte, src, dst [4]byte) uint16 {
-	pseudoHeader := []byte{
-		src[0], src[1], src[2], src[3],
-		dst[0], dst[1], dst[2], dst[3],
-		0,
-		6,
-		0,
-		byte(len(data)),
-	}
-
-	totalLength := len(pseudoHeader) + len(data)
-	if totalLength%2 != 0 {
-		totalLength++
-	}
+// recvICMP waits for an ICMP unreachable error caused by a probe to raddr:dport
+func recvICMP(conn *net.IPConn, raddr net.IP, sport uint16, dport uint16, deadline time.Time, res chan<- *tcpResponse) {
+	conn.SetReadDeadline(deadline)
 
-	d := make([]byte, 0, totalLength)
-	d = append(d, pseudoHeader...)
-	d = append(d, data...)
+	buff := make([]byte, 1500)
+	for {
+		n, _, err := conn.ReadFrom(buff)
+		if err != nil {
+			if ne, ok := err.(net.Error); ok && ne.Timeout() {
+				return
+			}
+			continue
+		}
 
-	var sum uint32
-	for i := 0; i < len(d)-1; i += 2 {
-		sum += uint32(uint16(d[i])<<8 | uint16(d[i+1]))
-	}
+		// Destination unreachable with a code a firewall would send
+		icmp, err := packet.ParseICMP(buff[:n])
+		if err != nil || icmp.Type != packet.ICMPDestinationUnreachable {
+			continue
+		}
+		switch icmp.Code {
+		case 1, 2, 3, 9, 10, 13:
+		default:
+			continue
+		}
 
-	sum = (sum >> 16) + (sum & 0xffff)
-	sum = sum + (sum >> 16)
-	return ^uint16(sum)
-}
+		// The error quotes the IP header and start of the TCP header of the probe
+		ip, err := packet.ParseIPv4(icmp.Payload)
+		if err != nil || ip.Protocol != packet.ProtocolTCP || !ip.Dst.Equal(raddr) || len(ip.Payload) < 4 {
+			continue
+		}
+		if binary.BigEndian.Uint16(ip.Payload[0:2]) != sport || binary.BigEndian.Uint16(ip.Payload[2:4]) != dport {
+			continue
+		}
 
-func ipstr2Bytes(addr string) [4]byte {
-	s := strings.Split(addr, ".")
-	b0, _ := strconv.Atoi(s[0])
-	b1, _ := strconv.Atoi(s[1])
-	b2, _ := strconv.Atoi(s[2])
-	b3, _ := strconv.Atoi(s[3])
-	return [4]byte{byte(b0), byte(b1), byte(b2), byte(b3)}
+		res <- &tcpResponse{Unreachable: true}
+		return
+	}
 }
 
 func random(min, max int) int {
 	return rand.Intn(max-min) + min
 }
+
+// String returns the name of the scan type
+func (s ScanType) String() string {
+	switch s {
+	case ScanConnect:
+		return "connect"
+	case ScanSYN:
+		return "syn"
+	case ScanFIN:
+		return "fin"
+	case ScanNULL:
+		return "null"
+	case ScanXmas:
+		return "xmas"
+	case ScanACK:
+		return "ack"
+	case ScanWindow:
+		return "window"
+	}
+	return fmt.Sprintf("ScanType(%d)", int(s))
+}
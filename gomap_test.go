@@ -1,10 +1,24 @@
-package gomap_test
+package gomap
 
 import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
+	_ "time/tzdata"
 
-	"github.com/JustinTimperio/gomap"
+	"github.com/JustinTimperio/gomap/packet"
 )
 
 func TestMain(m *testing.M) {
@@ -14,8 +28,8 @@ func TestMain(m *testing.M) {
 		stealth  = false
 	)
 
-	// results, err := gomap.ScanIP("192.168.1.1", proto, fastscan, stealth)
-	results, err := gomap.ScanRange(proto, fastscan, stealth)
+	// results, err := ScanIP("192.168.1.1", proto, fastscan, stealth)
+	results, err := ScanRange(proto, fastscan, stealth)
 	if err != nil {
 		panic(err)
 	} else {
@@ -28,4 +42,2259 @@ func TestMain(m *testing.M) {
 	} else {
 		fmt.Println(j)
 	}
+
+	os.Exit(m.Run())
+}
+
+func TestParseServices(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    []Service
+		wantErr bool
+	}{
+		{
+			name:  "frequency and comment",
+			input: "ssh\t22/tcp\t0.182286\t# Secure Shell Login\n",
+			want:  []Service{{Name: "ssh", Port: 22, Protocol: "tcp", Frequency: 0.182286, Comment: "Secure Shell Login"}},
+		},
+		{
+			name:  "no frequency column",
+			input: "# comment line\n\ndomain 53/UDP\n",
+			want:  []Service{{Name: "domain", Port: 53, Protocol: "udp"}},
+		},
+		{
+			name:  "later entry replaces earlier",
+			input: "http 80/tcp 0.4\nwww 80/tcp 0.5\n",
+			want:  []Service{{Name: "www", Port: 80, Protocol: "tcp", Frequency: 0.5}},
+		},
+		{name: "missing port", input: "ssh\n", wantErr: true},
+		{name: "missing protocol", input: "ssh 22\n", wantErr: true},
+		{name: "port out of range", input: "ssh 70000/tcp\n", wantErr: true},
+		{name: "invalid frequency", input: "ssh 22/tcp often\n", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, err := ParseServices(strings.NewReader(tt.input))
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := db.All()
+			if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestServicesLookup(t *testing.T) {
+	db, err := ParseServices(strings.NewReader("http 80/tcp 0.5\nsnmp 161/udp 0.4\nhttps 443/tcp 0.2\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		port  int
+		proto string
+		want  string
+	}{
+		{80, "tcp", "http"},
+		{161, "udp", "snmp"},
+		// Another protocol is used as a guess
+		{161, "tcp", "snmp"},
+		{8080, "tcp", "unknown"},
+	}
+	for _, tt := range tests {
+		if got := db.Name(tt.port, tt.proto); got != tt.want {
+			t.Errorf("Name(%d, %s) = %s, want %s", tt.port, tt.proto, got, tt.want)
+		}
+	}
+
+	top := db.Top("tcp", 1)
+	if len(top) != 1 || top[0].Port != 80 {
+		t.Errorf("Top(tcp, 1) = %v", top)
+	}
+}
+
+func TestServicesTop(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  []int
+	}{
+		{
+			name:  "by frequency",
+			input: "a 1/tcp 0.1\nb 2/tcp 0.3\nc 3/tcp 0.2\n",
+			want:  []int{2, 3, 1},
+		},
+		{
+			name:  "common ports without frequency",
+			input: "a 1/tcp\nb 4/tcp\nssh 22/tcp\nhttp 80/tcp\n",
+			want:  []int{22, 80, 1, 4},
+		},
+		{
+			name:  "frequency before common ports",
+			input: "ssh 22/tcp\na 1/tcp 0.1\n",
+			want:  []int{1, 22},
+		},
+	}
+	for _, tt := range tests {
+		db, err := ParseServices(strings.NewReader(tt.input))
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got []int
+		for _, s := range db.Top("tcp", 0) {
+			got = append(got, s.Port)
+		}
+		if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+			t.Errorf("%s: Top = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+
+	// The fastscan ports of the embedded database are the common ports
+	top := DefaultServices().Top("tcp", len(commonPorts))
+	for _, s := range top {
+		if !commonPorts[s.Port] {
+			t.Errorf("DefaultServices().Top ranks uncommon port %d", s.Port)
+		}
+	}
+}
+
+func TestServiceFilesDoNotChangeServices(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "services")
+	if err := ioutil.WriteFile(path, []byte("custom 9999/tcp 0.9\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	shared, err := ParseServices(strings.NewReader("http 80/tcp 0.5\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	opts := ScanOptions{Services: shared, ServiceFiles: []string{path}}
+	if _, err := prepareScan(&opts); err != nil {
+		t.Fatal(err)
+	}
+
+	if opts.Services.Name(9999, "tcp") != "custom" {
+		t.Error("service file was not loaded")
+	}
+	if shared.Name(9999, "tcp") != "unknown" {
+		t.Error("service file was merged into the shared database")
+	}
+
+	opts = ScanOptions{ServiceFiles: []string{path}}
+	if _, err := prepareScan(&opts); err != nil {
+		t.Fatal(err)
+	}
+	if DefaultServices().Name(9999, "tcp") == "custom" {
+		t.Error("service file was merged into the default database")
+	}
+}
+
+func TestParseProbes(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		check   func(t *testing.T, db *ProbeDB)
+		wantErr bool
+	}{
+		{
+			name: "probe with matches",
+			input: `Exclude T:9100-9107
+Probe TCP GetRequest q|GET / HTTP/1.0\r\n\r\n|
+rarity 1
+ports 80,8000-8010
+sslports 443
+totalwaitms 6000
+fallback NULL
+match http m|^HTTP/1\.[01] \d+.*\r\nServer: nginx/([\d.]+)|s p/nginx/ v/$1/ cpe:/a:nginx:nginx:$1/
+softmatch http m|^HTTP/1\.[01] \d+|i
+match bad m|(?<=x)y|
+`,
+			check: func(t *testing.T, db *ProbeDB) {
+				probes := db.Probes()
+				if len(probes) != 1 {
+					t.Fatalf("got %d probes", len(probes))
+				}
+				p := probes[0]
+				if p.Protocol != "tcp" || p.Name != "GetRequest" || string(p.Payload) != "GET / HTTP/1.0\r\n\r\n" {
+					t.Errorf("probe = %s %s %q", p.Protocol, p.Name, p.Payload)
+				}
+				if p.Rarity != 1 || p.TotalWait != 6*time.Second || len(p.Fallback) != 1 {
+					t.Errorf("rarity %d, wait %s, fallback %v", p.Rarity, p.TotalWait, p.Fallback)
+				}
+				if !p.ports.contains(8005) || p.ports.contains(8011) || !p.sslports.contains(443) {
+					t.Errorf("ports = %v, sslports = %v", p.ports, p.sslports)
+				}
+				// The lookbehind pattern is skipped
+				if len(p.Matches) != 2 || p.Matches[0].Product != "nginx" || !p.Matches[1].Soft {
+					t.Fatalf("matches = %+v", p.Matches)
+				}
+				if len(p.Matches[0].CPE) != 1 || p.Matches[0].CPE[0] != "cpe:/a:nginx:nginx:$1" {
+					t.Errorf("cpe = %v", p.Matches[0].CPE)
+				}
+				if !db.exclude["tcp"].contains(9100) || db.exclude["udp"].contains(9100) {
+					t.Errorf("exclude = %v", db.exclude)
+				}
+			},
+		},
+		{
+			name:  "escaped payload",
+			input: `Probe UDP DNS q|\0\x01\\\t|` + "\n",
+			check: func(t *testing.T, db *ProbeDB) {
+				if p := db.Probes()[0]; string(p.Payload) != "\x00\x01\\\t" {
+					t.Errorf("payload = %q", p.Payload)
+				}
+			},
+		},
+		{name: "match before probe", input: "match http m|x|\n", wantErr: true},
+		{name: "invalid protocol", input: "Probe SCTP x q||\n", wantErr: true},
+		{name: "unterminated payload", input: "Probe TCP x q|abc\n", wantErr: true},
+		{name: "rarity out of range", input: "Probe TCP x q||\nrarity 10\n", wantErr: true},
+		{name: "invalid port range", input: "Probe TCP x q||\nports 90-80\n", wantErr: true},
+		{name: "unknown directive", input: "Probe TCP x q||\nfoo bar\n", wantErr: true},
+		{name: "unknown version field", input: "Probe TCP x q||\nmatch a m|x| z/y/\n", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, err := ParseProbes(strings.NewReader(tt.input))
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			tt.check(t, db)
+		})
+	}
+}
+
+func TestExpandTemplate(t *testing.T) {
+	groups := []string{"all", "2.4.1", "a\x01b", "x_y_z", "\x01\x02"}
+	tests := []struct {
+		tmpl string
+		want string
+	}{
+		{"Apache httpd", "Apache httpd"},
+		{"$1", "2.4.1"},
+		{"v$1 ($9)", "v2.4.1 ()"},
+		{"$P(2)", "ab"},
+		{`$SUBST(3,"_",".")`, "x.y.z"},
+		{`$I(4,">")`, "258"},
+		{`$I(4,"<")`, "513"},
+		{"cost $", "cost $"},
+		{" $1 ", "2.4.1"},
+	}
+	for _, tt := range tests {
+		if got := expandTemplate(tt.tmpl, groups); got != tt.want {
+			t.Errorf("expandTemplate(%q) = %q, want %q", tt.tmpl, got, tt.want)
+		}
+	}
+}
+
+func TestProbeFilesDoNotChangeProbes(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "probes")
+	if err := ioutil.WriteFile(path, []byte("Probe TCP Custom q|hi|\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	shared := NewProbeDB()
+	opts := ScanOptions{VersionDetection: true, Probes: shared, ProbeFiles: []string{path}}
+	if _, err := prepareScan(&opts); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(opts.Probes.Probes()) != 1 {
+		t.Error("probe file was not loaded")
+	}
+	if len(shared.Probes()) != 0 {
+		t.Error("probe file was merged into the shared database")
+	}
+}
+
+func TestParseVulnFeed(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		cpe     string
+		want    []string
+		wantErr bool
+	}{
+		{
+			name: "1.1 feed",
+			input: `{"CVE_Items": [{
+				"cve": {"CVE_data_meta": {"ID": "CVE-2018-15473"}, "description": {"description_data": [{"lang": "en", "value": "user enumeration"}]}},
+				"configurations": {"nodes": [{"cpe_match": [{"vulnerable": true, "cpe23Uri": "cpe:2.3:a:openbsd:openssh:*:*:*:*:*:*:*:*", "versionEndIncluding": "7.7"}]}]},
+				"impact": {"baseMetricV3": {"cvssV3": {"baseScore": 5.3, "baseSeverity": "MEDIUM"}}}
+			}]}`,
+			cpe:  "cpe:/a:openbsd:openssh:7.4",
+			want: []string{"CVE-2018-15473 (medium 5.3)"},
+		},
+		{
+			name: "2.0 api with nested nodes",
+			input: `{"vulnerabilities": [{"cve": {
+				"id": "CVE-2021-41773",
+				"descriptions": [{"lang": "es", "value": "x"}, {"lang": "en", "value": "path traversal"}],
+				"configurations": [{"nodes": [{"children": [{"cpeMatch": [
+					{"vulnerable": true, "criteria": "cpe:2.3:a:apache:http_server:2.4.49:*:*:*:*:*:*:*"},
+					{"vulnerable": false, "criteria": "cpe:2.3:o:linux:linux_kernel:-:*:*:*:*:*:*:*"}
+				]}]}]}],
+				"metrics": {"cvssMetricV31": [{"cvssData": {"baseScore": 7.5, "baseSeverity": "HIGH"}}]}
+			}}]}`,
+			cpe:  "cpe:/a:apache:http_server:2.4.49",
+			want: []string{"CVE-2021-41773 (high 7.5)"},
+		},
+		{
+			name:  "version outside the range",
+			input: `{"CVE_Items": [{"configurations": {"nodes": [{"cpe_match": [{"vulnerable": true, "cpe23Uri": "cpe:2.3:a:openbsd:openssh:*:*:*:*:*:*:*:*", "versionEndExcluding": "7.4"}]}]}}]}`,
+			cpe:   "cpe:/a:openbsd:openssh:7.4",
+		},
+		{name: "not a feed", input: `{"foo": 1}`, wantErr: true},
+		{name: "invalid json", input: `{`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, err := ParseVulnFeed(strings.NewReader(tt.input))
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			cpe, err := ParseCPE(tt.cpe)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []string
+			for _, v := range db.Match(cpe) {
+				got = append(got, v.String())
+			}
+			if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompareVersions(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"1.0", "1.0", 0},
+		{"1.0", "1.0.0", 0},
+		{"1.2", "1.10", -1},
+		{"2.4.49", "2.4.5", 1},
+		{"2.0rc1", "2.0", -1},
+		{"7.4p1", "7.4p2", -1},
+		{"1.0a", "1.0B", -1},
+		{"01.2", "1.2", 0},
+	}
+	for _, tt := range tests {
+		if got := compareVersions(tt.a, tt.b); got != tt.want {
+			t.Errorf("compareVersions(%s, %s) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestVulnEntryAffects(t *testing.T) {
+	openssh := func(version, update string) CPE {
+		return CPE{Part: "a", Vendor: "openbsd", Product: "openssh", Version: version, Update: update}
+	}
+	tests := []struct {
+		name  string
+		entry vulnEntry
+		cpe   CPE
+		want  bool
+	}{
+		{"fixed version", vulnEntry{cpe: openssh("7.4", "")}, openssh("7.4", "p1"), true},
+		{"other fixed version", vulnEntry{cpe: openssh("7.4", "")}, openssh("7.5", ""), false},
+		{"fixed update", vulnEntry{cpe: openssh("7.4", "p1")}, openssh("7.4", "p2"), false},
+		{"unknown update", vulnEntry{cpe: openssh("7.4", "p1")}, openssh("7.4", ""), true},
+		{"start including", vulnEntry{cpe: openssh("", ""), startInclude: "7.0"}, openssh("7.0", ""), true},
+		{"start excluding", vulnEntry{cpe: openssh("", ""), startExclude: "7.0"}, openssh("7.0", ""), false},
+		{"end including", vulnEntry{cpe: openssh("", ""), endInclude: "7.7"}, openssh("7.7", ""), true},
+		{"end excluding", vulnEntry{cpe: openssh("", ""), endExclude: "7.7"}, openssh("7.7", ""), false},
+		{"every version", vulnEntry{cpe: openssh("-", "")}, openssh("", ""), true},
+		{"no version with range", vulnEntry{cpe: openssh("", ""), endExclude: "7.7"}, openssh("", ""), false},
+	}
+	for _, tt := range tests {
+		if got := tt.entry.affects(tt.cpe); got != tt.want {
+			t.Errorf("%s: affects = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestScopeCheckAddress(t *testing.T) {
+	tests := []struct {
+		name   string
+		scope  *Scope
+		target string
+		ip     string
+		want   bool
+	}{
+		{"nil allows private", nil, "192.168.1.10", "192.168.1.10", true},
+		{"nil refuses public", nil, "example.com", "93.184.216.34", false},
+		{"allow public", &Scope{AllowPublic: true}, "93.184.216.34", "93.184.216.34", true},
+		{"allowed range", &Scope{Allow: []string{"10.0.0.0/24"}}, "10.0.0.5", "10.0.0.5", true},
+		{"outside allowed range", &Scope{Allow: []string{"10.0.0.0/24"}}, "10.0.1.5", "10.0.1.5", false},
+		{"allowed public range", &Scope{Allow: []string{"93.184.216.0/24"}}, "93.184.216.34", "93.184.216.34", true},
+		{"allowed domain", &Scope{Allow: []string{"example.com"}}, "www.example.com", "10.0.0.5", true},
+		{"allowed domain public address", &Scope{Allow: []string{"example.com"}}, "www.example.com", "93.184.216.34", false},
+		{"allowed domain by address", &Scope{Allow: []string{"example.com"}}, "10.0.0.5", "10.0.0.5", false},
+		{"wildcard covers subdomains", &Scope{Allow: []string{"*.example.com"}}, "a.example.com", "10.0.0.5", true},
+		{"wildcard excludes domain", &Scope{Allow: []string{"*.example.com"}}, "example.com", "10.0.0.5", false},
+		{"denied address wins", &Scope{Allow: []string{"10.0.0.0/8"}, Deny: []string{"10.0.0.5"}}, "10.0.0.5", "10.0.0.5", false},
+		{"denied domain wins", &Scope{Allow: []string{"10.0.0.0/8"}, Deny: []string{"db.example.com"}}, "db.example.com.", "10.0.0.5", false},
+		{"ipv6 link local", nil, "fe80::1", "fe80::1", true},
+		{"ipv6 public", nil, "2606:4700::1111", "2606:4700::1111", false},
+		{"documentation range", nil, "192.0.2.1", "192.0.2.1", true},
+	}
+	for _, tt := range tests {
+		err := tt.scope.CheckAddress(tt.target, net.ParseIP(tt.ip))
+		if (err == nil) != tt.want {
+			t.Errorf("%s: CheckAddress(%s, %s) = %v, want allowed %v", tt.name, tt.target, tt.ip, err, tt.want)
+		}
+		if err != nil && !errors.Is(err, ErrOutOfScope) {
+			t.Errorf("%s: %v is not ErrOutOfScope", tt.name, err)
+		}
+	}
+
+	if err := (&Scope{Allow: []string{"10.0.0.0/33"}}).CheckAddress("10.0.0.1", net.ParseIP("10.0.0.1")); err == nil || errors.Is(err, ErrOutOfScope) {
+		t.Errorf("invalid range error = %v", err)
+	}
+}
+
+func TestParseSchedule(t *testing.T) {
+	tests := []struct {
+		spec    string
+		wantErr bool
+	}{
+		{spec: "*/15 * * * *"},
+		{spec: "0 9-17 * * mon-fri"},
+		{spec: "30 2 1,15 jan,jul *"},
+		{spec: "5/10 * * * 7"},
+		{spec: "@daily"},
+		{spec: "@every 90m"},
+		{spec: "* * * *", wantErr: true},
+		{spec: "60 * * * *", wantErr: true},
+		{spec: "0 0 0 * *", wantErr: true},
+		{spec: "0 0 * 13 *", wantErr: true},
+		{spec: "0 0 * * 8", wantErr: true},
+		{spec: "0 5-1 * * *", wantErr: true},
+		{spec: "*/0 * * * *", wantErr: true},
+		{spec: "0 0 * * funday", wantErr: true},
+		{spec: "@every 10ms", wantErr: true},
+		{spec: "@sometimes", wantErr: true},
+	}
+	for _, tt := range tests {
+		_, err := ParseSchedule(tt.spec)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseSchedule(%q) error = %v, want error %v", tt.spec, err, tt.wantErr)
+		}
+	}
+}
+
+func TestScheduleNext(t *testing.T) {
+	load := func(name string) *time.Location {
+		loc, err := time.LoadLocation(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return loc
+	}
+	kolkata := load("Asia/Kolkata")
+	adelaide := load("Australia/Adelaide")
+	newYork := load("America/New_York")
+	santiago := load("America/Santiago")
+
+	tests := []struct {
+		name string
+		spec string
+		from time.Time
+		want time.Time
+	}{
+		{"next minute", "* * * * *", time.Date(2024, 5, 1, 10, 0, 30, 0, time.UTC), time.Date(2024, 5, 1, 10, 1, 0, 0, time.UTC)},
+		{"steps", "*/15 * * * *", time.Date(2024, 5, 1, 10, 16, 0, 0, time.UTC), time.Date(2024, 5, 1, 10, 30, 0, 0, time.UTC)},
+		{"next day", "0 2 * * *", time.Date(2024, 5, 1, 3, 0, 0, 0, time.UTC), time.Date(2024, 5, 2, 2, 0, 0, 0, time.UTC)},
+		{"weekday", "0 9 * * mon", time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC), time.Date(2024, 5, 6, 9, 0, 0, 0, time.UTC)},
+		{"day of month or week", "0 0 13 * fri", time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC), time.Date(2024, 5, 3, 0, 0, 0, 0, time.UTC)},
+		{"leap day", "0 0 29 2 *", time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), time.Date(2028, 2, 29, 0, 0, 0, 0, time.UTC)},
+		{"never", "0 0 30 2 *", time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), time.Time{}},
+		{"every", "@every 90m", time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC), time.Date(2024, 5, 1, 11, 30, 0, 0, time.UTC)},
+		{"half hour offset", "0 2 * * *", time.Date(2024, 5, 1, 12, 0, 0, 0, kolkata), time.Date(2024, 5, 2, 2, 0, 0, 0, kolkata)},
+		{"half hour offset hourly", "@hourly", time.Date(2024, 5, 1, 12, 10, 0, 0, kolkata), time.Date(2024, 5, 1, 13, 0, 0, 0, kolkata)},
+		{"half hour offset with dst", "0 3 * * *", time.Date(2024, 4, 6, 12, 0, 0, 0, adelaide), time.Date(2024, 4, 7, 3, 0, 0, 0, adelaide)},
+		// 02:30 does not exist on the day clocks go forward
+		{"spring forward", "30 2 * * *", time.Date(2024, 3, 10, 0, 0, 0, 0, newYork), time.Date(2024, 3, 11, 2, 30, 0, 0, newYork)},
+		{"after spring forward", "0 * * * *", time.Date(2024, 3, 10, 1, 30, 0, 0, newYork), time.Date(2024, 3, 10, 3, 0, 0, 0, newYork)},
+		{"fall back", "0 3 * * *", time.Date(2024, 11, 3, 0, 30, 0, 0, newYork), time.Date(2024, 11, 3, 3, 0, 0, 0, newYork)},
+		// Midnight does not exist on the day clocks go forward
+		{"skipped midnight", "0 12 * * *", time.Date(2024, 9, 7, 13, 0, 0, 0, santiago), time.Date(2024, 9, 8, 12, 0, 0, 0, santiago)},
+	}
+	for _, tt := range tests {
+		s, err := ParseSchedule(tt.spec)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := s.Next(tt.from); !got.Equal(tt.want) {
+			t.Errorf("%s: Next(%s) = %s, want %s", tt.name, tt.from, got, tt.want)
+		}
+	}
+}
+
+func TestDiffResults(t *testing.T) {
+	host := func(target, addr string, ports ...portResult) *IPScanResult {
+		return &IPScanResult{Hostname: target, Target: target, IP: []net.IP{net.ParseIP(addr)}, Address: net.ParseIP(addr), Results: ports}
+	}
+	open := func(port int, service string) portResult {
+		return portResult{Port: port, State: true, Status: PortOpen, Service: service}
+	}
+	ssh := func(version string) portResult {
+		p := open(22, "ssh")
+		p.Version = &ServiceVersion{Service: "ssh", Product: "OpenSSH", Version: version}
+		return p
+	}
+
+	tests := []struct {
+		name string
+		old  RangeScanResult
+		new  RangeScanResult
+		want string
+	}{
+		{
+			name: "no changes",
+			old:  RangeScanResult{host("10.0.0.1", "10.0.0.1", open(80, "http"))},
+			new:  RangeScanResult{host("10.0.0.1", "10.0.0.1", open(80, "http"))},
+		},
+		{
+			name: "opened and closed",
+			old:  RangeScanResult{host("10.0.0.1", "10.0.0.1", open(80, "http"), open(21, "ftp"))},
+			new:  RangeScanResult{host("10.0.0.1", "10.0.0.1", open(80, "http"), open(443, "https"))},
+			want: "+ 10.0.0.1:443 https\n- 10.0.0.1:21 ftp\n",
+		},
+		{
+			name: "new and gone hosts",
+			old:  RangeScanResult{host("10.0.0.1", "10.0.0.1", open(80, "http"))},
+			new:  RangeScanResult{host("10.0.0.2", "10.0.0.2", open(80, "http"))},
+			want: "+ Host: 10.0.0.2\n- Host: 10.0.0.1\n+ 10.0.0.2:80 http\n- 10.0.0.1:80 http\n",
+		},
+		{
+			name: "changed version",
+			old:  RangeScanResult{host("10.0.0.1", "10.0.0.1", ssh("8.9p1"))},
+			new:  RangeScanResult{host("10.0.0.1", "10.0.0.1", ssh("9.6p1"))},
+			want: "~ 10.0.0.1:22 ssh OpenSSH 9.6p1 (was ssh OpenSSH 8.9p1)\n",
+		},
+		{
+			// A name scanned at another address is the same host
+			name: "name with a new address",
+			old:  RangeScanResult{host("www.example.com", "10.0.0.1", open(443, "https"))},
+			new:  RangeScanResult{host("www.example.com", "10.0.0.2", open(443, "https"))},
+		},
+		{
+			name: "closed ports are ignored",
+			old:  RangeScanResult{host("10.0.0.1", "10.0.0.1", open(80, "http"), portResult{Port: 81, Status: PortClosed})},
+			new:  RangeScanResult{host("10.0.0.1", "10.0.0.1", open(80, "http"))},
+		},
+	}
+	for _, tt := range tests {
+		d := DiffResults(tt.old, tt.new)
+		if got := d.String(); got != tt.want {
+			t.Errorf("%s: diff =\n%s\nwant\n%s", tt.name, got, tt.want)
+		}
+		if d.Empty() != (tt.want == "") {
+			t.Errorf("%s: Empty = %v", tt.name, d.Empty())
+		}
+	}
+}
+
+func TestCheckpointResume(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "scan.checkpoint")
+	opts := ScanOptions{Checkpoint: path, SNMPCommunities: []string{"s3cret"}}
+	cp := newCheckpoint(opts, []string{"10.0.0.1"})
+	ip := net.ParseIP("10.0.0.1")
+	result := &IPScanResult{Target: "10.0.0.1", Address: ip, IP: []net.IP{ip}}
+	if err := cp.hostDone("10.0.0.1", result); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "s3cret") {
+		t.Fatalf("checkpoint contains the SNMP community: %s", data)
+	}
+
+	if _, err := ResumeScan(path); !errors.Is(err, ErrCommunitiesRequired) {
+		t.Fatalf("ResumeScan without communities: err = %v, want ErrCommunitiesRequired", err)
+	}
+	results, err := ResumeScan(path, "s3cret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 1 || !results[0].Address.Equal(ip) {
+		t.Fatalf("ResumeScan = %+v, want the checkpointed host", results)
+	}
+
+	// Progress saved by the resumed scan must not write the community either
+	data, err = ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "s3cret") {
+		t.Fatalf("resumed checkpoint contains the SNMP community: %s", data)
+	}
+}
+
+func TestProbeFlags(t *testing.T) {
+	tests := []struct {
+		scan ScanType
+		want packet.TCPFlags
+	}{
+		{ScanSYN, packet.TCPSyn},
+		{ScanFIN, packet.TCPFin},
+		{ScanNULL, 0},
+		{ScanXmas, packet.TCPFin | packet.TCPPsh | packet.TCPUrg},
+		{ScanACK, packet.TCPAck},
+		{ScanWindow, packet.TCPAck},
+	}
+	for _, tt := range tests {
+		if got := probeFlags(tt.scan); got != tt.want {
+			t.Errorf("probeFlags(%s) = %v, want %v", tt.scan, got, tt.want)
+		}
+	}
+
+	if got := ScanType(42).String(); got != "ScanType(42)" {
+		t.Errorf("String() = %s", got)
+	}
+}
+
+func TestClassifyResponse(t *testing.T) {
+	synAck := &tcpResponse{Flags: packet.TCPSyn | packet.TCPAck}
+	rst := &tcpResponse{Flags: packet.TCPRst | packet.TCPAck}
+	rstWindow := &tcpResponse{Flags: packet.TCPRst, Window: 1024}
+	unreachable := &tcpResponse{Unreachable: true}
+
+	tests := []struct {
+		scan ScanType
+		resp *tcpResponse
+		want PortState
+	}{
+		{ScanSYN, synAck, PortOpen},
+		{ScanSYN, rst, PortClosed},
+		{ScanSYN, nil, PortFiltered},
+		{ScanSYN, unreachable, PortFiltered},
+		{ScanFIN, nil, PortOpenOrFiltered},
+		{ScanFIN, rst, PortClosed},
+		{ScanNULL, nil, PortOpenOrFiltered},
+		{ScanXmas, rst, PortClosed},
+		{ScanXmas, unreachable, PortFiltered},
+		{ScanACK, rst, PortUnfiltered},
+		{ScanACK, nil, PortFiltered},
+		{ScanWindow, rstWindow, PortOpen},
+		{ScanWindow, rst, PortClosed},
+		{ScanWindow, nil, PortFiltered},
+	}
+	for _, tt := range tests {
+		if got := classifyResponse(tt.scan, tt.resp); got != tt.want {
+			t.Errorf("classifyResponse(%s, %+v) = %v, want %v", tt.scan, tt.resp, got, tt.want)
+		}
+	}
+}
+
+func TestParseTraceReplies(t *testing.T) {
+	dst := net.ParseIP("10.0.0.9").To4()
+	router := net.ParseIP("10.0.0.1").To4()
+	const base, id = 20000, 0x1234
+
+	// quote returns an ICMP error of type quoting a probe with an 8 byte header
+	quote := func(typ uint8, proto uint8, header []byte) []byte {
+		ip := &packet.IPv4{TTL: 1, Protocol: proto, Src: net.ParseIP("10.0.0.2"), Dst: dst, Payload: header}
+		quoted, err := ip.Marshal()
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, _ := (&packet.ICMP{Type: typ, Payload: quoted}).Marshal()
+		return b
+	}
+	ports := func(sport uint16) []byte {
+		h := make([]byte, 8)
+		h[0], h[1] = byte(sport>>8), byte(sport)
+		h[3] = 80
+		return h
+	}
+	echo := func(ttl uint16) []byte {
+		h := make([]byte, 8)
+		h[4], h[5], h[6], h[7] = id>>8, id&0xff, byte(ttl>>8), byte(ttl)
+		return h
+	}
+	echoReply, _ := (&packet.ICMP{Type: packet.ICMPEchoReply, Rest: id<<16 | 4}).Marshal()
+
+	tests := []struct {
+		name        string
+		b           []byte
+		from        net.IP
+		proto       string
+		wantTTL     int
+		wantReached bool
+		wantOK      bool
+	}{
+		{"tcp time exceeded", quote(packet.ICMPTimeExceeded, packet.ProtocolTCP, ports(base+3)), router, "tcp", 3, false, true},
+		{"udp unreachable from host", quote(packet.ICMPDestinationUnreachable, packet.ProtocolUDP, ports(base+5)), dst, "udp", 5, true, true},
+		{"udp unreachable from router", quote(packet.ICMPDestinationUnreachable, packet.ProtocolUDP, ports(base+5)), router, "udp", 5, false, true},
+		{"icmp time exceeded", quote(packet.ICMPTimeExceeded, packet.ProtocolICMP, echo(2)), router, "icmp", 2, false, true},
+		{"echo reply", echoReply, dst, "icmp", 4, true, true},
+		{"echo reply from another host", echoReply, router, "icmp", 0, false, false},
+		{"echo reply to tcp trace", echoReply, dst, "tcp", 0, false, false},
+		{"other protocol quoted", quote(packet.ICMPTimeExceeded, packet.ProtocolUDP, ports(base+3)), router, "tcp", 0, false, false},
+		{"port out of range", quote(packet.ICMPTimeExceeded, packet.ProtocolTCP, ports(base+31)), router, "tcp", 0, false, false},
+		{"other icmp id", quote(packet.ICMPTimeExceeded, packet.ProtocolICMP, []byte{0, 0, 0, 0, 0, 1, 0, 2}), router, "icmp", 0, false, false},
+		{"truncated", []byte{11, 0}, router, "tcp", 0, false, false},
+	}
+	for _, tt := range tests {
+		ttl, reached, ok := parseTraceICMP(tt.b, tt.from, dst, tt.proto, base, id, 30)
+		if ttl != tt.wantTTL || reached != tt.wantReached || ok != tt.wantOK {
+			t.Errorf("%s: parseTraceICMP = %d, %v, %v, want %d, %v, %v", tt.name, ttl, reached, ok, tt.wantTTL, tt.wantReached, tt.wantOK)
+		}
+	}
+
+	synAck, err := (&packet.TCP{SrcPort: 80, DstPort: base + 7, Flags: packet.TCPSyn | packet.TCPAck}).Marshal(dst, router)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ttl, ok := parseTraceTCP(synAck, base, 30); ttl != 7 || !ok {
+		t.Errorf("parseTraceTCP = %d, %v, want 7, true", ttl, ok)
+	}
+	if _, ok := parseTraceTCP(synAck, base+10, 30); ok {
+		t.Error("parseTraceTCP accepted a reply to another port")
+	}
+}
+
+func TestTracePath(t *testing.T) {
+	ip := net.ParseIP("10.0.0.1")
+	tests := []struct {
+		name string
+		hops []TraceHop
+		want int
+	}{
+		{"cut at the host", []TraceHop{{IP: ip}, {IP: ip, Reached: true}, {IP: ip}}, 2},
+		{"cut after the last reply", []TraceHop{{IP: ip}, {}, {IP: ip}, {}, {}}, 3},
+		{"no replies", []TraceHop{{}, {}}, 0},
+	}
+	for _, tt := range tests {
+		if got := tracePath(tt.hops); len(got) != tt.want {
+			t.Errorf("%s: %d hops, want %d", tt.name, len(got), tt.want)
+		}
+	}
+}
+
+func TestParseOSDB(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    int
+		wantErr bool
+	}{
+		{name: "fingerprints", input: "# comment\nFingerprint Linux | 5.x | general purpose\nT1 TTL=64 W=64240|65160\n\nFingerprint Windows | 10 | general purpose\nT1 TTL=128\n", want: 2},
+		{name: "missing class", input: "Fingerprint Linux | 5.x\n", wantErr: true},
+		{name: "test before fingerprint", input: "T1 TTL=64\n", wantErr: true},
+		{name: "invalid attribute", input: "Fingerprint Linux | 5.x | general purpose\nT1 TTL\n", wantErr: true},
+		{name: "unknown attribute", input: "Fingerprint Linux | 5.x | general purpose\nT1 XX=1\n", wantErr: true},
+	}
+	for _, tt := range tests {
+		db, err := parseOSDB(strings.NewReader(tt.input))
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: err = %v, wantErr %v", tt.name, err, tt.wantErr)
+			continue
+		}
+		if len(db) != tt.want {
+			t.Errorf("%s: %d fingerprints, want %d", tt.name, len(db), tt.want)
+		}
+	}
+
+	db, err := parseOSDB(strings.NewReader("Fingerprint Linux | 5.x | general purpose\nT1 TTL=64 W=64240|65160\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := db[0].tests["T1"]["W"]; fmt.Sprint(got) != "[64240 65160]" {
+		t.Errorf("W = %v", got)
+	}
+	if db[0].family != "Linux" || db[0].version != "5.x" || db[0].class != "general purpose" {
+		t.Errorf("fingerprint = %+v", db[0])
+	}
+
+	if _, err := parseOSDB(strings.NewReader(defaultOSFile)); err != nil {
+		t.Errorf("embedded os database: %v", err)
+	}
+}
+
+func TestMatchOS(t *testing.T) {
+	db, err := parseOSDB(strings.NewReader(`
+Fingerprint Linux | 5.x | general purpose
+T1 TTL=64 DF=Y W=64240 O=MSTNW
+T5 R=Y TTL=64 W=0
+U1 R=Y|N TTL=64
+
+Fingerprint Windows | 10 | general purpose
+T1 TTL=128 DF=Y W=65535 O=MNWNNS
+T5 R=Y TTL=128 W=0
+U1 R=N
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		obs  osObservation
+		want []string
+	}{
+		{
+			name: "linux",
+			obs: osObservation{
+				"T1": {"R": "Y", "TTL": "64", "DF": "Y", "W": "64240", "O": "MSTNW"},
+				"T5": {"R": "Y", "TTL": "64", "W": "0"},
+				"U1": {"R": "N"},
+			},
+			want: []string{"Linux 5.x (general purpose) 100%"},
+		},
+		{
+			// Without an open port T1 is not sent and not scored
+			name: "windows without an open port",
+			obs: osObservation{
+				"T5": {"R": "Y", "TTL": "128", "W": "0"},
+				"U1": {"R": "N"},
+			},
+			want: []string{"Windows 10 (general purpose) 100%", "Linux 5.x (general purpose) 70%"},
+		},
+		{
+			name: "nothing matches",
+			obs: osObservation{
+				"T5": {"R": "N"},
+				"U1": {"R": "Y", "TTL": "255"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		var got []string
+		for _, m := range matchOS(tt.obs, db) {
+			got = append(got, m.String())
+		}
+		if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+			t.Errorf("%s: matchOS = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestSourceAddress(t *testing.T) {
+	laddr, err := sourceAddress(net.ParseIP("127.0.0.1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if laddr != "127.0.0.1" {
+		t.Errorf("sourceAddress(127.0.0.1) = %s, want 127.0.0.1", laddr)
+	}
+
+	// Without SourceIP or Interface scans leave from the default route
+	route, err := RouteTo(defaultRouteTarget)
+	if err != nil {
+		t.Skipf("no default route: %v", err)
+	}
+	laddr, err = localAddress(&ScanOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if laddr != route.Source.String() {
+		t.Errorf("localAddress = %s, want %s from the default route", laddr, route.Source)
+	}
+}
+
+func TestCreateHostRange(t *testing.T) {
+	tests := []struct {
+		cidr  string
+		first string
+		last  string
+		size  int
+	}{
+		{"192.168.1.0/24", "192.168.1.1", "192.168.1.254", 254},
+		{"192.168.1.77/24", "192.168.1.1", "192.168.1.254", 254},
+		{"10.0.0.0/30", "10.0.0.1", "10.0.0.2", 2},
+		// Point to point links have no network or broadcast address
+		{"10.0.0.0/31", "10.0.0.0", "10.0.0.1", 2},
+		{"10.0.0.5/32", "10.0.0.5", "10.0.0.5", 1},
+		{"255.255.255.255/32", "255.255.255.255", "255.255.255.255", 1},
+	}
+	for _, tt := range tests {
+		hosts, err := createHostRange(tt.cidr)
+		if err != nil {
+			t.Fatalf("createHostRange(%s): %v", tt.cidr, err)
+		}
+		if len(hosts) != tt.size || hosts[0] != tt.first || hosts[len(hosts)-1] != tt.last {
+			t.Errorf("createHostRange(%s) = %d hosts %s - %s, want %d hosts %s - %s",
+				tt.cidr, len(hosts), hosts[0], hosts[len(hosts)-1], tt.size, tt.first, tt.last)
+		}
+		size, err := rangeSize(tt.cidr)
+		if err != nil || size != tt.size {
+			t.Errorf("rangeSize(%s) = %d, %v, want %d", tt.cidr, size, err, tt.size)
+		}
+	}
+
+	for _, cidr := range []string{"10.0.0.0", "fd00::/120"} {
+		if _, err := createHostRange(cidr); err == nil {
+			t.Errorf("createHostRange(%s) did not fail", cidr)
+		}
+	}
+}
+
+func TestLocalRangesFollowDefaultRoute(t *testing.T) {
+	route, err := RouteTo(defaultRouteTarget)
+	if err != nil {
+		t.Skipf("no default route: %v", err)
+	}
+	laddr, err := localAddress(&ScanOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ranges, err := getLocalRanges(&ScanOptions{}, laddr)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ifaces, err := Interfaces()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, iface := range ifaces {
+		if iface.Name != route.Interface {
+			continue
+		}
+		for _, a := range iface.Addrs {
+			if a.IP.Equal(route.Source) {
+				want := (&net.IPNet{IP: a.IP.Mask(a.Mask), Mask: a.Mask}).String()
+				if len(ranges) != 1 || ranges[0] != want {
+					t.Errorf("getLocalRanges = %v, want [%s] of %s", ranges, want, route.Interface)
+				}
+				return
+			}
+		}
+	}
+	t.Errorf("source %s of the default route is not on %s", route.Source, route.Interface)
+}
+
+func TestMergeAddressResults(t *testing.T) {
+	a := net.ParseIP("10.0.0.1")
+	b := net.ParseIP("10.0.0.2")
+	open := func(port int) portResult {
+		return portResult{Port: port, State: true, Status: PortOpen, Service: "http"}
+	}
+	closed := func(port int) portResult { return portResult{Port: port, Status: PortClosed, Service: "http"} }
+
+	tests := []struct {
+		name  string
+		addrs []AddressResult
+		want  map[int]bool
+	}{
+		{
+			name:  "open on any address",
+			addrs: []AddressResult{{IP: a, Results: []portResult{closed(80), open(443)}}, {IP: b, Results: []portResult{open(80), closed(443)}}},
+			want:  map[int]bool{80: true, 443: true},
+		},
+		{
+			name:  "closed everywhere",
+			addrs: []AddressResult{{IP: a, Results: []portResult{closed(80)}}, {IP: b, Results: []portResult{closed(80)}}},
+			want:  map[int]bool{80: false},
+		},
+		{
+			name:  "port on one address",
+			addrs: []AddressResult{{IP: a, Results: []portResult{open(22)}}, {IP: b}},
+			want:  map[int]bool{22: true},
+		},
+	}
+	for _, tt := range tests {
+		got := make(map[int]bool)
+		for _, r := range mergeAddressResults(tt.addrs) {
+			if _, ok := got[r.Port]; ok {
+				t.Errorf("%s: port %d merged twice", tt.name, r.Port)
+			}
+			got[r.Port] = r.State
+		}
+		if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+			t.Errorf("%s: merged %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestDifferences(t *testing.T) {
+	a := net.ParseIP("10.0.0.1")
+	b := net.ParseIP("10.0.0.2")
+
+	tests := []struct {
+		name  string
+		addrs []AddressResult
+		want  []string
+	}{
+		{
+			name: "same state",
+			addrs: []AddressResult{
+				{IP: a, Results: []portResult{{Port: 80, State: true, Status: PortOpen, Service: "http"}}},
+				{IP: b, Results: []portResult{{Port: 80, State: true, Status: PortOpen, Service: "http"}}},
+			},
+		},
+		{
+			name: "open on one address",
+			addrs: []AddressResult{
+				{IP: a, Results: []portResult{{Port: 80, State: true, Status: PortOpen, Service: "http"}}},
+				{IP: b, Results: []portResult{{Port: 80, Status: PortFiltered, Service: "http"}}},
+			},
+			want: []string{"80\thttp (open on 10.0.0.1, filtered on 10.0.0.2)"},
+		},
+		{
+			// Results without a status fall back to the open flag
+			name: "missing on one address",
+			addrs: []AddressResult{
+				{IP: a, Results: []portResult{{Port: 22, State: true, Service: "ssh"}, {Port: 80, Service: "http"}}},
+				{IP: b, Results: []portResult{{Port: 80, Service: "http"}}},
+			},
+			want: []string{"22\tssh (open on 10.0.0.1)"},
+		},
+		{
+			name:  "single address",
+			addrs: []AddressResult{{IP: a, Results: []portResult{{Port: 80, State: true}}}},
+		},
+	}
+	for _, tt := range tests {
+		var got []string
+		for _, d := range (&IPScanResult{Addresses: tt.addrs}).Differences() {
+			got = append(got, d.String())
+		}
+		if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+			t.Errorf("%s: Differences = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestResolverZeroValue(t *testing.T) {
+	var r Resolver
+	r.addNames("10.0.0.1", []string{"printer.local"})
+	if _, err := r.LookupIP("10.0.0.1"); err != nil {
+		t.Fatal(err)
+	}
+	if r.Timeout != 5*time.Second || r.CacheTTL != 5*time.Minute || r.FailureTTL != 30*time.Second {
+		t.Errorf("defaults = %v, %v, %v", r.Timeout, r.CacheTTL, r.FailureTTL)
+	}
+
+	r2 := Resolver{Servers: []string{"10.0.0.53", "10.0.0.54:5353"}}
+	r2.setup()
+	if fmt.Sprint(r2.Servers) != "[10.0.0.53:53 10.0.0.54:5353]" {
+		t.Errorf("Servers = %v", r2.Servers)
+	}
+}
+
+func TestResolverCache(t *testing.T) {
+	r := &Resolver{CacheTTL: time.Hour, FailureTTL: 10 * time.Millisecond}
+	r.setup()
+
+	ip := net.ParseIP("10.0.0.7")
+	r.store(r.forward, "cached.example", lookupResult{ips: []net.IP{ip}})
+	r.store(r.forward, "failed.example", lookupResult{err: errors.New("no such host")})
+	r.store(r.reverse, "10.0.0.7", lookupResult{names: []string{"cached.example"}})
+
+	// Cached answers are returned without a query
+	ips, err := r.LookupIP("cached.example")
+	if err != nil || len(ips) != 1 || !ips[0].Equal(ip) {
+		t.Errorf("LookupIP = %v, %v", ips, err)
+	}
+	if _, err := r.LookupIP("failed.example"); err == nil {
+		t.Error("cached failure was not returned")
+	}
+
+	r.addNames("10.0.0.7", []string{"printer.local"})
+	names, err := r.HostNames("10.0.0.7")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []HostName{{Name: "cached.example", Confirmed: true}, {Name: "printer.local"}}
+	if fmt.Sprint(names) != fmt.Sprint(want) {
+		t.Errorf("HostNames = %v, want %v", names, want)
+	}
+
+	// Failures expire sooner than answers
+	time.Sleep(20 * time.Millisecond)
+	if _, ok := r.cached(r.forward, "failed.example"); ok {
+		t.Error("failed lookup did not expire")
+	}
+	if _, ok := r.cached(r.forward, "cached.example"); !ok {
+		t.Error("answer expired before its TTL")
+	}
+
+	// Added names do not expire with the cache
+	r.addNames("10.0.0.8", []string{"tv.local"})
+	r.reverse["10.0.0.8"] = lookupResult{err: errors.New("no such host"), expires: time.Now().Add(-time.Second)}
+	if _, ok := r.cached(r.reverse, "10.0.0.8"); ok {
+		t.Error("expired lookup was returned")
+	}
+	if r.names["10.0.0.8"][0] != "tv.local" {
+		t.Error("added name was dropped")
+	}
+}
+
+// dnsName encodes name as uncompressed dns labels
+func dnsName(name string) []byte {
+	var b []byte
+	for _, l := range strings.Split(strings.TrimSuffix(name, "."), ".") {
+		b = append(b, byte(len(l)))
+		b = append(b, l...)
+	}
+	return append(b, 0)
+}
+
+// axfrServer answers a single zone transfer on a local port with messages
+func axfrServer(t *testing.T, messages ...packet.DNS) string {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ln.Close() })
+
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		buff := make([]byte, 512)
+		n, err := conn.Read(buff)
+		if err != nil || n < 4 {
+			return
+		}
+		query, err := packet.ParseDNS(buff[2:n])
+		if err != nil {
+			return
+		}
+		for _, m := range messages {
+			m.ID = query.ID
+			m.Flags |= packet.DNSResponse
+			b, _ := m.Marshal()
+			conn.Write(append([]byte{byte(len(b) >> 8), byte(len(b))}, b...))
+		}
+	}()
+	return ln.Addr().String()
+}
+
+func TestAXFR(t *testing.T) {
+	soaData := append(append(dnsName("ns1.example.com"), dnsName("admin.example.com")...), make([]byte, 20)...)
+	soa := packet.DNSResource{Name: "example.com", Type: packet.DNSTypeSOA, Class: packet.DNSClassINET, Data: soaData}
+	a := func(name string, ip string) packet.DNSResource {
+		return packet.DNSResource{Name: name, Type: packet.DNSTypeA, Class: packet.DNSClassINET, Data: net.ParseIP(ip).To4()}
+	}
+	cname := packet.DNSResource{Name: "mail.example.com", Type: packet.DNSTypeCNAME, Class: packet.DNSClassINET, Data: dnsName("mx.provider.net")}
+	txt := packet.DNSResource{Name: "example.com", Type: packet.DNSTypeTXT, Class: packet.DNSClassINET, Data: []byte("\x05hello")}
+
+	// The zone is split over two messages and ends with the SOA record
+	server := axfrServer(t,
+		packet.DNS{Answers: []packet.DNSResource{soa, a("www.example.com", "10.0.0.1"), cname}},
+		packet.DNS{Answers: []packet.DNSResource{txt, a("www.example.com", "10.0.0.2"), a("other.org", "10.0.0.3"), soa}},
+	)
+	records, err := axfr("example.com", server, 5*time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 7 {
+		t.Fatalf("axfr returned %d records, want 7", len(records))
+	}
+	if records[0].Target != "ns1.example.com." {
+		t.Errorf("SOA primary = %q", records[0].Target)
+	}
+
+	// CNAMEs are resolved, cached here so nothing is queried
+	resolver := NewResolver()
+	resolver.store(resolver.forward, "mail.example.com", lookupResult{ips: []net.IP{net.ParseIP("192.168.5.5")}})
+	var got []string
+	for _, s := range zoneSubdomains("example.com", records, resolver) {
+		got = append(got, fmt.Sprintf("%s %v %s", s.Name, s.IPs, s.Source))
+	}
+	want := []string{"www.example.com [10.0.0.1 10.0.0.2] axfr", "mail.example.com [192.168.5.5] axfr"}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("zoneSubdomains = %v, want %v", got, want)
+	}
+
+	refused := axfrServer(t, packet.DNS{Flags: 5})
+	if _, err := axfr("example.com", refused, 5*time.Second); err == nil {
+		t.Error("refused transfer did not fail")
+	}
+}
+
+// kexInit builds a KEXINIT payload from ten comma separated name-lists
+func kexInit(lists ...string) []byte {
+	b := append([]byte{sshMsgKexInit}, make([]byte, 16)...)
+	for _, l := range lists {
+		b = appendSSHString(b, []byte(l))
+	}
+	return append(b, 0, 0, 0, 0, 0)
+}
+
+func TestParseKexInit(t *testing.T) {
+	full := kexInit("curve25519-sha256,diffie-hellman-group14-sha1", "ssh-ed25519,rsa-sha2-512",
+		"aes128-ctr", "aes128-ctr,aes256-cbc", "hmac-sha2-256", "hmac-sha2-256", "none,zlib@openssh.com", "none", "", "")
+
+	tests := []struct {
+		name    string
+		payload []byte
+		want    [][]string
+		wantErr bool
+	}{
+		{
+			name:    "offer",
+			payload: full,
+			want: [][]string{
+				{"curve25519-sha256", "diffie-hellman-group14-sha1"}, {"ssh-ed25519", "rsa-sha2-512"},
+				{"aes128-ctr"}, {"aes128-ctr", "aes256-cbc"}, {"hmac-sha2-256"}, {"hmac-sha2-256"},
+				{"none", "zlib@openssh.com"}, {"none"}, nil, nil,
+			},
+		},
+		{name: "not a KEXINIT", payload: append([]byte{sshMsgKexDHInit}, full[1:]...), wantErr: true},
+		{name: "no cookie", payload: []byte{sshMsgKexInit, 1, 2}, wantErr: true},
+		{name: "truncated list", payload: full[:40], wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := parseKexInit(tt.payload)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: err = %v, wantErr %v", tt.name, err, tt.wantErr)
+			continue
+		}
+		if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+			t.Errorf("%s: parseKexInit = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+// sshServer runs a fake SSH server offering kex that answers the key
+// exchange with hostKey and records the public key it was sent
+func sshServer(t *testing.T, kex string, hostKey []byte, clientPub chan<- []byte) string {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ln.Close() })
+
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go func(conn net.Conn) {
+				defer conn.Close()
+				conn.Write([]byte("SSH-2.0-OpenSSH_9.6\r\n"))
+				writeSSHPacket(conn, kexInit(kex, "ssh-ed25519", "aes128-ctr", "aes128-ctr",
+					"hmac-sha2-256", "hmac-sha2-256", "none", "none", "", ""))
+
+				r := bufio.NewReader(conn)
+				if _, err := r.ReadString('\n'); err != nil {
+					return
+				}
+				if _, err := readSSHPacket(r); err != nil {
+					return
+				}
+				init, err := readSSHPacket(r)
+				if err != nil || len(init) == 0 || init[0] != sshMsgKexDHInit {
+					return
+				}
+				pub, _, _ := sshString(init[1:])
+				clientPub <- pub
+				writeSSHPacket(conn, appendSSHString([]byte{sshMsgKexReply}, hostKey))
+			}(conn)
+		}
+	}()
+	return ln.Addr().String()
+}
+
+func TestSSHHandshake(t *testing.T) {
+	hostKey := appendSSHString(nil, []byte("ssh-ed25519"))
+	hostKey = appendSSHString(hostKey, make([]byte, 32))
+
+	tests := []struct {
+		kex    string
+		pubLen int
+	}{
+		{"curve25519-sha256", 32},
+		{"curve25519-sha256@libssh.org", 32},
+		{"ecdh-sha2-nistp256", 65},
+		{"diffie-hellman-group14-sha256", 0},
+	}
+	for _, tt := range tests {
+		pubs := make(chan []byte, 1)
+		info, err := inspectSSH(sshServer(t, tt.kex, hostKey, pubs), 5*time.Second)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.kex, err)
+		}
+		if info.Banner != "SSH-2.0-OpenSSH_9.6" || fmt.Sprint(info.KexAlgorithms) != "["+tt.kex+"]" {
+			t.Errorf("%s: info = %+v", tt.kex, info)
+		}
+		if len(info.HostKeys) != 1 || info.HostKeys[0].Type != "ssh-ed25519" {
+			t.Fatalf("%s: host keys = %v", tt.kex, info.HostKeys)
+		}
+
+		pub := <-pubs
+		if tt.pubLen > 0 && len(pub) != tt.pubLen {
+			t.Errorf("%s: public key of %d bytes, want %d", tt.kex, len(pub), tt.pubLen)
+		}
+		if tt.pubLen == 0 && len(pub) < 255 {
+			t.Errorf("%s: public key of %d bytes", tt.kex, len(pub))
+		}
+	}
+}
+
+// nodeStatusEntry encodes a name of a node status answer
+func nodeStatusEntry(name string, suffix byte, group bool) []byte {
+	b := []byte(fmt.Sprintf("%-15s", name))
+	b = append(b, suffix, 0x04, 0)
+	if group {
+		b[16] |= 0x80
+	}
+	return b
+}
+
+func TestParseNodeStatus(t *testing.T) {
+	mac := []byte{0x00, 0x15, 0x5d, 0x01, 0x02, 0x03}
+	data := []byte{4}
+	data = append(data, nodeStatusEntry("FILESRV", 0x00, false)...)
+	data = append(data, nodeStatusEntry("CORP", 0x00, true)...)
+	data = append(data, nodeStatusEntry("FILESRV", 0x20, false)...)
+	data = append(data, nodeStatusEntry("CORP", 0x1e, true)...)
+	data = append(data, mac...)
+	data = append(data, make([]byte, 40)...)
+
+	// A response quoting the wildcard question with one NBSTAT answer
+	name := append([]byte{32}, "CK"+strings.Repeat("A", 30)...)
+	name = append(name, 0)
+	resp := []byte{0x12, 0x34, 0x84, 0x00, 0, 1, 0, 1, 0, 0, 0, 0}
+	resp = append(resp, name...)
+	resp = append(resp, 0, 0x21, 0, 1)
+	resp = append(resp, 0xc0, 12, 0, 0x21, 0, 1, 0, 0, 0, 0, byte(len(data)>>8), byte(len(data)))
+	resp = append(resp, data...)
+
+	answer, err := nodeStatusAnswer(resp, 0x1234)
+	if err != nil {
+		t.Fatal(err)
+	}
+	info, err := parseNodeStatus(answer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.String() != `CORP\FILESRV` || info.MAC.String() != "00:15:5d:01:02:03" || len(info.Names) != 4 {
+		t.Errorf("info = %s %s %v", info, info.MAC, info.Names)
+	}
+	if n := info.Names[2]; n.Name != "FILESRV" || n.Suffix != 0x20 || n.Group {
+		t.Errorf("file server name = %+v", n)
+	}
+
+	// with returns a copy of the response with the byte at i set to v
+	with := func(i int, v byte) []byte {
+		b := append([]byte{}, resp...)
+		b[i] = v
+		return b
+	}
+	errs := []struct {
+		name string
+		b    []byte
+		id   uint16
+	}{
+		{"other id", resp, 0x4321},
+		{"query", with(2, 0x04), 0x1234},
+		{"no answer", with(7, 0), 0x1234},
+		{"truncated header", resp[:8], 0x1234},
+		{"truncated data", resp[:len(resp)-10], 0x1234},
+	}
+	for _, tt := range errs {
+		if _, err := nodeStatusAnswer(tt.b, tt.id); err == nil {
+			t.Errorf("%s: nodeStatusAnswer did not fail", tt.name)
+		}
+	}
+	if _, err := parseNodeStatus(data[:30]); err == nil {
+		t.Error("parseNodeStatus accepted a truncated name table")
+	}
+
+	// A zero MAC, as sent by Samba, is left out
+	info, err = parseNodeStatus(append(data[:1+4*18:1+4*18], make([]byte, 6)...))
+	if err != nil || info.MAC != nil {
+		t.Errorf("zero MAC = %v, %v", info.MAC, err)
+	}
+}
+
+func TestParseSMBNegotiate(t *testing.T) {
+	smb1 := func(wordCount byte, dialect uint16, mode byte) []byte {
+		b := make([]byte, 40)
+		copy(b, "\xffSMB")
+		b[32] = wordCount
+		b[33], b[34] = byte(dialect), byte(dialect>>8)
+		b[35] = mode
+		return b
+	}
+	smb1Tests := []struct {
+		name     string
+		resp     []byte
+		required bool
+		wantErr  bool
+	}{
+		{"signing required", smb1(17, 0, 0x0f), true, false},
+		{"signing enabled", smb1(17, 0, 0x03), false, false},
+		{"dialect refused", smb1(1, 0xffff, 0), false, true},
+		{"error without words", smb1(0, 0, 0), false, true},
+		{"smb2 reply", append([]byte("\xfeSMB"), make([]byte, 40)...), false, true},
+	}
+	for _, tt := range smb1Tests {
+		required, err := parseSMB1Negotiate(tt.resp)
+		if required != tt.required || (err != nil) != tt.wantErr {
+			t.Errorf("SMB1 %s: %v, %v", tt.name, required, err)
+		}
+	}
+
+	smb2 := func(status uint32, mode uint16, dialect uint16) []byte {
+		b := make([]byte, 64+65)
+		copy(b, "\xfeSMB")
+		b[8], b[9], b[10], b[11] = byte(status), byte(status>>8), byte(status>>16), byte(status>>24)
+		b[66], b[67] = byte(mode), byte(mode>>8)
+		b[68], b[69] = byte(dialect), byte(dialect>>8)
+		return b
+	}
+	smb2Tests := []struct {
+		name     string
+		resp     []byte
+		required bool
+		wantErr  bool
+	}{
+		{"signing required", smb2(0, 0x03, 0x0311), true, false},
+		{"signing enabled", smb2(0, 0x01, 0x0311), false, false},
+		{"other dialect", smb2(0, 0x01, 0x0210), false, true},
+		{"not supported", smb2(0xc00000bb, 0, 0), false, true},
+		{"truncated", smb2(0, 0x03, 0x0311)[:66], false, true},
+	}
+	for _, tt := range smb2Tests {
+		required, err := parseSMB2Negotiate(tt.resp, 0x0311)
+		if required != tt.required || (err != nil) != tt.wantErr {
+			t.Errorf("SMB2 %s: %v, %v", tt.name, required, err)
+		}
+	}
+}
+
+func TestMDNSRecords(t *testing.T) {
+	srvData := append([]byte{0, 0, 0, 0, 0x02, 0x77}, dnsName("printer.local")...)
+	resp := packet.DNS{
+		Flags: packet.DNSResponse | packet.DNSAuthoritative,
+		Answers: []packet.DNSResource{
+			{Name: "_services._dns-sd._udp.local", Type: packet.DNSTypePTR, Class: packet.DNSClassINET, Data: dnsName("_ipp._tcp.local")},
+			{Name: "_ipp._tcp.local", Type: packet.DNSTypePTR, Class: packet.DNSClassINET, Data: dnsName("Office._ipp._tcp.local")},
+			{Name: "_ssh._tcp.local", Type: packet.DNSTypePTR, Class: packet.DNSClassINET, Data: dnsName("nas._ssh._tcp.local")},
+		},
+		Additionals: []packet.DNSResource{
+			{Name: "Office._ipp._tcp.local", Type: packet.DNSTypeSRV, Class: packet.DNSClassINET | 0x8000, Data: srvData},
+			{Name: "Office._ipp._tcp.local", Type: packet.DNSTypeTXT, Class: packet.DNSClassINET, Data: []byte("\x00\x0bty=LaserJet\x00")},
+			{Name: "Printer.local", Type: packet.DNSTypeA, Class: packet.DNSClassINET, Data: []byte{192, 168, 1, 20}},
+			{Name: "printer.local", Type: packet.DNSTypeA, Class: packet.DNSClassINET, Data: []byte{192, 168, 1, 20}},
+			{Name: "printer.local", Type: packet.DNSTypeAAAA, Class: packet.DNSClassINET, Data: net.ParseIP("fe80::1")},
+		},
+	}
+	msg, err := resp.Marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+	parsed, err := packet.ParseDNS(msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	recs := newMDNSRecords()
+	for _, r := range append(parsed.Answers, parsed.Additionals...) {
+		recs.add(r)
+	}
+	if !recs.types["_ipp._tcp.local."] {
+		t.Errorf("service types = %v", recs.types)
+	}
+
+	var got []string
+	for _, s := range recs.services() {
+		got = append(got, fmt.Sprintf("%s %v", s, s.IPs))
+	}
+	want := []string{
+		"Office (_ipp._tcp) printer.local:631 [ty=LaserJet] [192.168.1.20 fe80::1]",
+		"nas (_ssh._tcp) :0 []",
+	}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("services = %q, want %q", got, want)
+	}
+
+	// Only ipv4 addresses are matched with scanned hosts
+	hosts := mdnsHosts(recs.services())
+	if len(hosts) != 1 || len(hosts["192.168.1.20"]) != 1 {
+		t.Errorf("mdnsHosts = %v", hosts)
+	}
+	if names := mdnsNames(hosts); fmt.Sprint(names) != "map[192.168.1.20:[printer.local.]]" {
+		t.Errorf("mdnsNames = %v", names)
+	}
+}
+
+func TestParseSSDPReply(t *testing.T) {
+	ip := net.ParseIP("192.168.1.1")
+	tests := []struct {
+		name  string
+		reply string
+		want  string
+		ok    bool
+	}{
+		{
+			name:  "reply",
+			reply: "HTTP/1.1 200 OK\r\nCACHE-CONTROL: max-age=1800\r\nLOCATION: http://192.168.1.1:1900/rootDesc.xml\r\nSERVER: Linux/5.4 UPnP/1.1 MiniUPnPd/2.2\r\nST: upnp:rootdevice\r\nUSN: uuid:1234::upnp:rootdevice\r\n\r\n",
+			want:  "http://192.168.1.1:1900/rootDesc.xml Linux/5.4 UPnP/1.1 MiniUPnPd/2.2 uuid:1234::upnp:rootdevice",
+			ok:    true,
+		},
+		{name: "no location", reply: "HTTP/1.1 200 OK\r\nST: upnp:rootdevice\r\n\r\n"},
+		{name: "error status", reply: "HTTP/1.1 404 Not Found\r\nLOCATION: http://192.168.1.1/\r\n\r\n"},
+		{name: "search from another client", reply: "M-SEARCH * HTTP/1.1\r\nHOST: 239.255.255.250:1900\r\n\r\n"},
+	}
+	for _, tt := range tests {
+		d, ok := parseSSDPReply([]byte(tt.reply), ip)
+		if ok != tt.ok {
+			t.Errorf("%s: ok = %v", tt.name, ok)
+			continue
+		}
+		if ok && fmt.Sprintf("%s %s %s", d.Location, d.Server, d.USN) != tt.want {
+			t.Errorf("%s: device = %+v", tt.name, d)
+		}
+	}
+}
+
+func TestDescribeUPnP(t *testing.T) {
+	const desc = `<?xml version="1.0"?>
+<root xmlns="urn:schemas-upnp-org:device-1-0">
+ <device>
+  <deviceType>urn:schemas-upnp-org:device:InternetGatewayDevice:1</deviceType>
+  <friendlyName> Router </friendlyName>
+  <manufacturer>Acme</manufacturer>
+  <modelName>GW</modelName>
+  <modelNumber>7</modelNumber>
+  <serviceList><service><serviceType>urn:schemas-upnp-org:service:Layer3Forwarding:1</serviceType></service></serviceList>
+  <deviceList><device><serviceList>
+   <service><serviceType>urn:schemas-upnp-org:service:WANIPConnection:1</serviceType></service>
+   <service><serviceType>urn:schemas-upnp-org:service:Layer3Forwarding:1</serviceType></service>
+  </serviceList></device></deviceList>
+ </device>
+</root>`
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/redirect" {
+			http.Redirect(w, r, "/desc.xml", http.StatusFound)
+			return
+		}
+		w.Write([]byte(desc))
+	}))
+	defer srv.Close()
+
+	client := upnpClient(5 * time.Second)
+	local := net.ParseIP("127.0.0.1")
+
+	tests := []struct {
+		name     string
+		ip       net.IP
+		location string
+		want     string
+	}{
+		{
+			name:     "description",
+			ip:       local,
+			location: srv.URL + "/desc.xml",
+			want:     "Router (Acme GW 7) [urn:schemas-upnp-org:service:Layer3Forwarding:1, urn:schemas-upnp-org:service:WANIPConnection:1]",
+		},
+		// Locations are only fetched from the device that replied
+		{name: "other host", ip: net.ParseIP("127.0.0.2"), location: srv.URL + "/desc.xml"},
+		{name: "redirect", ip: local, location: srv.URL + "/redirect"},
+		{name: "other scheme", ip: local, location: "file:///etc/passwd"},
+	}
+	for _, tt := range tests {
+		d := &UPnPDevice{IP: tt.ip, Location: tt.location}
+		describeUPnP(client, d)
+		if got := d.String(); tt.want != "" && got != tt.want {
+			t.Errorf("%s: device = %s, want %s", tt.name, got, tt.want)
+		}
+		if tt.want == "" && d.FriendlyName != "" {
+			t.Errorf("%s: description was read", tt.name)
+		}
+	}
+}
+
+func TestBEREncoding(t *testing.T) {
+	ints := []struct {
+		v    int64
+		want string
+	}{
+		{0, "00"},
+		{127, "7f"},
+		{128, "0080"},
+		{256, "0100"},
+		{-1, "ff"},
+		{-129, "ff7f"},
+		{2147483647, "7fffffff"},
+	}
+	for _, tt := range ints {
+		if got := fmt.Sprintf("%x", berInt(tt.v)); got != tt.want {
+			t.Errorf("berInt(%d) = %s, want %s", tt.v, got, tt.want)
+		}
+	}
+
+	// Lengths use the short form below 128 bytes and one or two bytes above
+	for _, n := range []int{0, 127, 128, 255, 256, 1500} {
+		value := make([]byte, n)
+		b := append(berTLV(berOctetString, value), 0xaa)
+		tag, got, rest, err := berRead(b)
+		if err != nil || tag != berOctetString || len(got) != n || len(rest) != 1 {
+			t.Errorf("berRead of %d bytes = %x, %d bytes, %x, %v", n, tag, len(got), rest, err)
+		}
+	}
+	for _, b := range [][]byte{{0x04}, {0x04, 0x05, 1, 2}, {0x04, 0x80}, {0x04, 0x84, 0, 0, 0, 1, 0}} {
+		if _, _, _, err := berRead(b); err == nil {
+			t.Errorf("berRead(%x) did not fail", b)
+		}
+	}
+
+	oids := []struct {
+		oid  string
+		want string
+	}{
+		{oidSysDescr, "2b06010201010100"},
+		{"1.3.6.1.4.1.311.1", "2b06010401823701"},
+		{"1.3.6.1.4294967295", "2b06018fffffff7f"},
+		{"2.5.4.3", "550403"},
+	}
+	for _, tt := range oids {
+		b, err := berEncodeOID(tt.oid)
+		if err != nil || fmt.Sprintf("%x", b) != tt.want {
+			t.Errorf("berEncodeOID(%s) = %x, %v, want %s", tt.oid, b, err, tt.want)
+			continue
+		}
+		if got, err := berDecodeOID(b); err != nil || got != tt.oid {
+			t.Errorf("berDecodeOID(%x) = %s, %v", b, got, err)
+		}
+	}
+	for _, oid := range []string{"1", "1.3.x", "1.3.6.4294967296"} {
+		if _, err := berEncodeOID(oid); err == nil {
+			t.Errorf("berEncodeOID(%s) did not fail", oid)
+		}
+	}
+	for _, b := range [][]byte{nil, {0x2b, 0x06, 0x82}} {
+		if _, err := berDecodeOID(b); err == nil {
+			t.Errorf("berDecodeOID(%x) did not fail", b)
+		}
+	}
+}
+
+func TestParseSNMPResponse(t *testing.T) {
+	varbind := func(oid string, tag byte, value []byte) []byte {
+		o, err := berEncodeOID(oid)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return berTLV(berSequence, append(berTLV(berOID, o), berTLV(tag, value)...))
+	}
+	message := func(pdu byte, id int64, status int64, varbinds ...[]byte) []byte {
+		body := berTLV(berInteger, berInt(id))
+		body = append(body, berTLV(berInteger, berInt(status))...)
+		body = append(body, berTLV(berInteger, []byte{0})...)
+		var list []byte
+		for _, vb := range varbinds {
+			list = append(list, vb...)
+		}
+		body = append(body, berTLV(berSequence, list)...)
+
+		msg := berTLV(berInteger, berInt(1))
+		msg = append(msg, berTLV(berOctetString, []byte("public"))...)
+		msg = append(msg, berTLV(pdu, body)...)
+		return berTLV(berSequence, msg)
+	}
+
+	resp := message(snmpResponse, 0x7fff0001, 0,
+		varbind(oidSysDescr, berOctetString, []byte("Linux router 5.15")),
+		varbind(oidSysUpTime, 0x43, []byte{0x01, 0x00, 0x00}),
+		varbind("1.3.6.1.2.1.2.2.1.2.1", berEndOfMib, nil),
+	)
+	vars, id, err := parseSNMPResponse(resp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id != 0x7fff0001 || len(vars) != 3 {
+		t.Fatalf("id = %#x, vars = %v", id, vars)
+	}
+	if vars[0].OID != oidSysDescr || string(vars[0].Value) != "Linux router 5.15" {
+		t.Errorf("sysDescr = %+v", vars[0])
+	}
+	if vars[1].OID != oidSysUpTime || berUint(vars[1].Value) != 65536 {
+		t.Errorf("sysUpTime = %+v", vars[1])
+	}
+	if vars[2].Tag != berEndOfMib {
+		t.Errorf("end of mib tag = %#x", vars[2].Tag)
+	}
+
+	errs := []struct {
+		name string
+		b    []byte
+	}{
+		{"error status", message(snmpResponse, 1, 2)},
+		{"request", message(snmpGet, 1, 0)},
+		{"truncated", resp[:len(resp)-4]},
+		{"not a sequence", berTLV(berOctetString, nil)},
+	}
+	for _, tt := range errs {
+		if _, _, err := parseSNMPResponse(tt.b); err == nil {
+			t.Errorf("%s: parseSNMPResponse did not fail", tt.name)
+		}
+	}
+}
+
+// testCheck is a check registered by the tests
+type testCheck struct {
+	name    string
+	port    int
+	run     func(ctx context.Context, t CheckTarget) ([]Finding, error)
+	applies func(t CheckTarget) bool
+}
+
+func (c testCheck) Name() string { return c.name }
+
+func (c testCheck) Applies(t CheckTarget) bool {
+	if c.applies != nil {
+		return c.applies(t)
+	}
+	return t.Port == c.port
+}
+
+func (c testCheck) Run(ctx context.Context, t CheckTarget) ([]Finding, error) {
+	return c.run(ctx, t)
+}
+
+func TestRunChecks(t *testing.T) {
+	found := func(ctx context.Context, t CheckTarget) ([]Finding, error) {
+		return []Finding{{Title: "found"}}, nil
+	}
+	stop := make(chan struct{})
+	defer close(stop)
+
+	RegisterCheck(testCheck{name: "test-found", port: 80, run: found})
+	RegisterCheck(testCheck{name: "test-failed", port: 80, run: func(ctx context.Context, t CheckTarget) ([]Finding, error) {
+		return []Finding{{Title: "partial"}}, errors.New("connection reset")
+	}})
+	RegisterCheck(testCheck{name: "test-honours-timeout", port: 80, run: func(ctx context.Context, t CheckTarget) ([]Finding, error) {
+		<-ctx.Done()
+		return []Finding{{Title: "too late"}}, ctx.Err()
+	}})
+	// Keeps reading the result long after it was abandoned
+	RegisterCheck(testCheck{name: "test-ignores-timeout", port: 80, run: func(ctx context.Context, t CheckTarget) ([]Finding, error) {
+		for {
+			select {
+			case <-stop:
+				return nil, nil
+			default:
+				_ = len(t.Result.Findings) + len(t.Result.Results)
+				time.Sleep(time.Millisecond)
+			}
+		}
+	}})
+	// Host checks run last and see the port findings
+	RegisterCheck(testCheck{
+		name:    "test-host",
+		applies: func(t CheckTarget) bool { return t.Port == 0 },
+		run: func(ctx context.Context, t CheckTarget) ([]Finding, error) {
+			return []Finding{{Title: fmt.Sprintf("%d findings", len(t.Result.Findings)), Severity: SeverityHigh}}, nil
+		},
+	})
+
+	scan := &IPScanResult{
+		Hostname: "web",
+		Results: []portResult{
+			{Port: 80, State: true, Status: PortOpen, Service: "http"},
+			{Port: 81, Status: PortClosed},
+		},
+	}
+	opts := &ScanOptions{
+		Proto:        "tcp",
+		Checks:       []string{"test-found", "test-failed", "test-honours-timeout", "test-ignores-timeout", "test-host"},
+		CheckWorkers: 2,
+		CheckTimeout: 50 * time.Millisecond,
+	}
+	start := time.Now()
+	runChecks("127.0.0.1", scan, opts)
+	if time.Since(start) > time.Second {
+		t.Errorf("runChecks took %s", time.Since(start))
+	}
+
+	var got []string
+	for _, f := range scan.Findings {
+		got = append(got, f.String())
+	}
+	want := []string{"[info] 80 test-found: found", "[high] test-host: 1 findings"}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("findings = %q, want %q", got, want)
+	}
+
+	// The abandoned check must not see the result change
+	for i := 0; i < 20; i++ {
+		scan.Findings = append(scan.Findings, Finding{Title: "later"})
+		scan.Results[0].Service = "changed"
+		time.Sleep(time.Millisecond)
+	}
+
+	if _, err := enabledChecks([]string{"no-such-check"}); err == nil {
+		t.Error("unknown check was enabled")
+	}
+}
+
+// checkServer serves each connection on a local port with handle and
+// returns the target of a check against it
+func checkServer(t *testing.T, service string, handle func(conn net.Conn, r *bufio.Reader)) CheckTarget {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ln.Close() })
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				handle(conn, bufio.NewReader(conn))
+			}()
+		}
+	}()
+	return CheckTarget{Host: "127.0.0.1", Port: ln.Addr().(*net.TCPAddr).Port, Proto: "tcp", Service: service}
+}
+
+// httpTarget serves body on / and /version of a local http server
+func httpTarget(t *testing.T, status int, body string) CheckTarget {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Server", "test")
+		w.WriteHeader(status)
+		w.Write([]byte(body))
+	}))
+	t.Cleanup(srv.Close)
+	return CheckTarget{Host: "127.0.0.1", Port: srv.Listener.Addr().(*net.TCPAddr).Port, Proto: "tcp", Service: "http"}
+}
+
+func TestMisconfigurationChecks(t *testing.T) {
+	ftp := func(pass string) func(net.Conn, *bufio.Reader) {
+		return func(conn net.Conn, r *bufio.Reader) {
+			conn.Write([]byte("220-Welcome\r\n220 FTP ready\r\n"))
+			r.ReadString('\n')
+			conn.Write([]byte("331 Send password\r\n"))
+			r.ReadString('\n')
+			conn.Write([]byte(pass + "\r\n"))
+		}
+	}
+	redis := func(reply string) func(net.Conn, *bufio.Reader) {
+		return func(conn net.Conn, r *bufio.Reader) {
+			r.ReadString('\n')
+			conn.Write([]byte(reply))
+		}
+	}
+	info := "# Server\r\nredis_version:7.2.4\r\nredis_mode:standalone\r\nos:Linux\r\n"
+	memcached := func(conn net.Conn, r *bufio.Reader) {
+		r.ReadString('\n')
+		conn.Write([]byte("STAT pid 1\r\nSTAT version 1.6.21\r\nSTAT curr_items 3\r\nEND\r\n"))
+	}
+	mongo := func(reply string) func(net.Conn, *bufio.Reader) {
+		return func(conn net.Conn, r *bufio.Reader) {
+			header := make([]byte, 4)
+			if _, err := io.ReadFull(r, header); err != nil {
+				return
+			}
+			io.CopyN(ioutil.Discard, r, int64(header[0])-4)
+			b := append(make([]byte, 4), make([]byte, 17)...)
+			b = append(b, reply...)
+			b[0] = byte(len(b))
+			conn.Write(b)
+		}
+	}
+
+	tests := []struct {
+		name   string
+		run    func(context.Context, CheckTarget) ([]Finding, error)
+		target CheckTarget
+		want   string
+	}{
+		{"ftp anonymous", checkFTPAnonymous, checkServer(t, "ftp", ftp("230 Login successful")), "Anonymous FTP login allowed (230 Login successful)"},
+		{"ftp denied", checkFTPAnonymous, checkServer(t, "ftp", ftp("530 Login incorrect")), ""},
+		{"redis open", checkRedis, checkServer(t, "redis", redis(fmt.Sprintf("$%d\r\n%s", len(info), info))), "Redis accepts commands without authentication (INFO returned redis_version 7.2.4)"},
+		{"redis password", checkRedis, checkServer(t, "redis", redis("-NOAUTH Authentication required.\r\n")), ""},
+		{"memcached", checkMemcached, checkServer(t, "memcached", memcached), "Memcached accepts commands without authentication (stats returned 3 values)"},
+		{"mongodb open", checkMongoDB, checkServer(t, "mongodb", mongo("\x04databases\x00\x03\x00\x02name\x00\x06\x00\x00\x00admin\x00\x00\x01ok\x00")), "MongoDB accessible without authentication (listDatabases succeeded without credentials)"},
+		{"mongodb auth", checkMongoDB, checkServer(t, "mongodb", mongo("\x02errmsg\x00\x0d\x00\x00\x00Unauthorized\x00")), ""},
+		{"elasticsearch", checkElasticsearch, httpTarget(t, 200, `{"name":"node-1","cluster_name":"logs","version":{"number":"8.12.0"}}`), "Elasticsearch API accessible without authentication (GET / returned cluster logs)"},
+		{"elasticsearch auth", checkElasticsearch, httpTarget(t, 401, `{"error":"security_exception"}`), ""},
+		{"docker", checkDockerAPI, httpTarget(t, 200, `{"Version":"24.0.7","ApiVersion":"1.43","Os":"linux","Arch":"amd64"}`), "Docker remote API exposed without authentication (GET /version returned Docker 24.0.7 (API 1.43))"},
+		{"directory listing", checkDirectoryListing, httpTarget(t, 200, "<html><title>Index of /backup</title></html>"), "Directory listing enabled (Index of /backup)"},
+		{"plain page", checkDirectoryListing, httpTarget(t, 200, "<html><title>Welcome</title></html>"), ""},
+	}
+	for _, tt := range tests {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		findings, err := tt.run(ctx, tt.target)
+		cancel()
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		var got string
+		for _, f := range findings {
+			got = f.Title + " (" + f.Detail + ")"
+		}
+		if len(findings) > 1 || got != tt.want {
+			t.Errorf("%s: findings = %v, want %q", tt.name, findings, tt.want)
+		}
+	}
+
+	// A target that never answers gives up with the context
+	silent := checkServer(t, "redis", func(conn net.Conn, r *bufio.Reader) { time.Sleep(time.Second) })
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := checkRedis(ctx, silent); err == nil {
+		t.Error("checkRedis did not time out")
+	}
+}
+
+func TestServiceCheckApplies(t *testing.T) {
+	enabled, err := enabledChecks([]string{"elasticsearch-noauth", "docker-api", "redis-noauth", "http-dirlist"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	byName := make(map[string]Check)
+	for _, c := range enabled {
+		byName[c.Name()] = c
+	}
+
+	tests := []struct {
+		check  string
+		target CheckTarget
+		want   bool
+	}{
+		{"redis-noauth", CheckTarget{Port: 7000, Proto: "tcp", Service: "redis"}, true},
+		// Default ports apply whatever the port was named
+		{"redis-noauth", CheckTarget{Port: 6379, Proto: "tcp", Service: "unknown"}, true},
+		{"redis-noauth", CheckTarget{Port: 7000, Proto: "tcp", Service: "unknown"}, false},
+		{"redis-noauth", CheckTarget{Port: 6379, Proto: "udp", Service: "redis"}, false},
+		{"redis-noauth", CheckTarget{Proto: "tcp"}, false},
+		// Version detection names Elasticsearch and Docker after http
+		{"elasticsearch-noauth", CheckTarget{Port: 9201, Proto: "tcp", Service: "http", Version: &ServiceVersion{Service: "http", Product: "Elasticsearch REST API"}}, true},
+		{"elasticsearch-noauth", CheckTarget{Port: 9201, Proto: "tcp", Service: "http", Version: &ServiceVersion{Service: "http", CPE: []string{"cpe:/a:elastic:elasticsearch:8.12.0"}}}, true},
+		{"elasticsearch-noauth", CheckTarget{Port: 9201, Proto: "tcp", Service: "http", Version: &ServiceVersion{Service: "http", Product: "nginx"}}, false},
+		{"docker-api", CheckTarget{Port: 4243, Proto: "tcp", Service: "http", Version: &ServiceVersion{Service: "http", Product: "Docker"}}, true},
+		{"http-dirlist", CheckTarget{Port: 8081, Proto: "tcp", Service: "http-alt"}, true},
+	}
+	for _, tt := range tests {
+		if got := byName[tt.check].Applies(tt.target); got != tt.want {
+			t.Errorf("%s.Applies(%d/%s %s %v) = %v, want %v", tt.check, tt.target.Port, tt.target.Proto, tt.target.Service, tt.target.Version, got, tt.want)
+		}
+	}
+}
+
+// readAuditLog returns the events written to path
+func readAuditLog(t *testing.T, path string) []AuditEvent {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var events []AuditEvent
+	for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+		var e AuditEvent
+		if err := json.Unmarshal([]byte(line), &e); err != nil {
+			t.Fatalf("invalid audit line %q: %v", line, err)
+		}
+		events = append(events, e)
+	}
+	return events
+}
+
+func TestAudit(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+	opts := &ScanOptions{AuditLog: path, Operator: "alice", SNMPCommunities: []string{"private", "s3cret"}}
+
+	a, err := startAudit(opts, "start", []string{"10.0.0.1", "10.0.0.2"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	results := RangeScanResult{
+		{
+			IP:       []net.IP{net.ParseIP("10.0.0.1")},
+			Results:  []portResult{{Port: 22, State: true, Status: PortOpen, Vulns: []Vulnerability{{ID: "CVE-2024-6387"}}}, {Port: 23, Status: PortClosed}},
+			Findings: []Finding{{Check: "ftp-anon"}},
+		},
+		nil,
+		{
+			IP:        []net.IP{net.ParseIP("10.0.0.2")},
+			Addresses: []AddressResult{{Findings: []Finding{{Check: "redis-noauth"}, {Check: "http-dirlist"}}}},
+		},
+	}
+	scanErr := errors.New("canceled")
+	if err := a.finish(results, scanErr); err != scanErr {
+		t.Errorf("finish = %v, want the scan error", err)
+	}
+
+	data, _ := ioutil.ReadFile(path)
+	if strings.Contains(string(data), "s3cret") || strings.Contains(string(data), "private") {
+		t.Errorf("audit log contains an SNMP community: %s", data)
+	}
+	if opts.SNMPCommunities[1] != "s3cret" {
+		t.Error("redacting changed the scan options")
+	}
+
+	events := readAuditLog(t, path)
+	if len(events) != 2 {
+		t.Fatalf("%d events, want 2", len(events))
+	}
+	start, end := events[0], events[1]
+	if start.Event != "start" || start.Operator != "alice" || start.ScanID == "" || len(start.Targets) != 2 {
+		t.Errorf("start = %+v", start)
+	}
+	if fmt.Sprint(start.Options.SNMPCommunities) != "[REDACTED REDACTED]" {
+		t.Errorf("communities = %v", start.Options.SNMPCommunities)
+	}
+	if end.Event != "end" || end.ScanID != start.ScanID || end.Error != "canceled" {
+		t.Errorf("end = %+v", end)
+	}
+	if end.Hosts != 2 || end.OpenPorts != 1 || end.Vulnerabilities != 1 || end.Findings != 3 {
+		t.Errorf("summary = %d hosts, %d open ports, %d vulnerabilities, %d findings", end.Hosts, end.OpenPorts, end.Vulnerabilities, end.Findings)
+	}
+
+	// Auditing is off without a log and a scan is refused if it cannot be recorded
+	if a, err := startAudit(&ScanOptions{}, "start", nil); a != nil || err != nil {
+		t.Errorf("startAudit without a log = %v, %v", a, err)
+	}
+	if _, err := startAudit(&ScanOptions{AuditLog: filepath.Join(path, "not-a-dir", "audit.log")}, "start", nil); err == nil {
+		t.Error("startAudit did not fail for an unwritable log")
+	}
+}
+
+func TestServerSubmit(t *testing.T) {
+	if _, err := NewServer(ServerOptions{}); err == nil {
+		t.Error("NewServer accepted a server without a token or scope")
+	}
+
+	s, err := newServer(ServerOptions{
+		Token:           "secret",
+		Scope:           &Scope{Allow: []string{"10.0.0.0/8"}},
+		AuditLog:        "/var/log/gomap-audit.log",
+		SourceIP:        "10.0.0.1",
+		MaxHosts:        256,
+		Checks:          []string{"ftp-anon"},
+		SNMPCommunities: []string{"public"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	j, err := s.Submit("10.1.1.1", ScanRequest{
+		Target:   "10.0.0.5",
+		Operator: "alice",
+		Options: ScanOptions{
+			Fastscan:        true,
+			AuditLog:        "/tmp/elsewhere",
+			Checkpoint:      "/etc/cron.d/gomap",
+			Scope:           &Scope{AllowPublic: true},
+			Interface:       "eth1",
+			SourceIP:        "192.0.2.1",
+			DNSServers:      []string{"198.51.100.1"},
+			AllSubnets:      true,
+			MaxHosts:        -1,
+			Checks:          []string{"ftp-anon"},
+			SNMPCommunities: []string{"public"},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if j.Status != JobQueued || j.Operator != "10.1.1.1" || j.ClaimedOperator != "alice" {
+		t.Errorf("job = %+v", j)
+	}
+	opts := s.jobs[j.ID].opts
+	if !opts.Fastscan || opts.AuditLog != "/var/log/gomap-audit.log" || opts.Checkpoint != "" || opts.Scope != s.opts.Scope {
+		t.Errorf("server settings not applied: %+v", opts)
+	}
+	if opts.Interface != "" || opts.SourceIP != "10.0.0.1" || opts.DNSServers != nil || opts.AllSubnets {
+		t.Errorf("client chose where the scan is sent from: %+v", opts)
+	}
+	if opts.MaxHosts != 256 || opts.Operator != "10.1.1.1" || opts.ClaimedOperator != "alice" {
+		t.Errorf("MaxHosts = %d, Operator = %q, ClaimedOperator = %q", opts.MaxHosts, opts.Operator, opts.ClaimedOperator)
+	}
+
+	// Requests may lower MaxHosts
+	j, _ = s.Submit("", ScanRequest{Options: ScanOptions{MaxHosts: 16}})
+	if got := s.jobs[j.ID].opts.MaxHosts; got != 16 {
+		t.Errorf("MaxHosts = %d, want 16", got)
+	}
+
+	refused := []ScanRequest{
+		{Target: "8.8.8.8"},
+		{Target: "10.0.0.5", Options: ScanOptions{Checks: []string{"all"}}},
+		{Target: "10.0.0.5", Options: ScanOptions{Checks: []string{"redis-noauth"}}},
+		{Target: "10.0.0.5", Options: ScanOptions{SNMPCommunities: []string{"private"}}},
+	}
+	for _, req := range refused {
+		if _, err := s.Submit("10.1.1.1", req); err == nil {
+			t.Errorf("Submit(%+v) was not refused", req)
+		}
+	}
+
+	// A server allowing every check takes any check but still caps hosts
+	all, _ := newServer(ServerOptions{Token: "secret", Checks: []string{"all"}, MaxHosts: -1})
+	j, err = all.Submit("", ScanRequest{Options: ScanOptions{Checks: []string{"all", "redis-noauth"}, MaxHosts: -1}})
+	if err != nil || all.jobs[j.ID].opts.MaxHosts != -1 {
+		t.Errorf("Submit = %v, MaxHosts = %d", err, all.jobs[j.ID].opts.MaxHosts)
+	}
+
+	// Queued jobs are canceled on Close and nothing is queued afterwards
+	s.Close()
+	for _, j := range s.Jobs() {
+		if j.Status != JobCanceled {
+			t.Errorf("job %s is %s after Close", j.ID, j.Status)
+		}
+	}
+	if _, err := s.Submit("10.1.1.1", ScanRequest{Target: "10.0.0.5"}); err != ErrServerClosed {
+		t.Errorf("Submit after Close = %v", err)
+	}
+}
+
+func TestServerHTTP(t *testing.T) {
+	s, err := newServer(ServerOptions{Token: "secret", QueueSize: 1, Scope: &Scope{Allow: []string{"127.0.0.1"}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := httptest.NewServer(s)
+	defer srv.Close()
+
+	do := func(method, path, token, body string) (*http.Response, map[string]interface{}) {
+		req, _ := http.NewRequest(method, srv.URL+path, strings.NewReader(body))
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		var v map[string]interface{}
+		data, _ := io.ReadAll(resp.Body)
+		json.Unmarshal(data, &v)
+		return resp, v
+	}
+
+	for _, token := range []string{"", "wrong", "secre"} {
+		resp, _ := do("GET", "/scans", token, "")
+		if resp.StatusCode != http.StatusUnauthorized || resp.Header.Get("WWW-Authenticate") != "Bearer" {
+			t.Errorf("token %q: status %d", token, resp.StatusCode)
+		}
+	}
+
+	resp, job := do("POST", "/scans", "secret", `{"Target": "127.0.0.1", "Operator": "alice", "Options": {"Fastscan": true}}`)
+	if resp.StatusCode != http.StatusAccepted || job["Operator"] != "127.0.0.1" || job["ClaimedOperator"] != "alice" {
+		t.Fatalf("submit: status %d, %v", resp.StatusCode, job)
+	}
+	id := job["ID"].(string)
+	if resp.Header.Get("Location") != "/scans/"+id {
+		t.Errorf("Location = %q", resp.Header.Get("Location"))
+	}
+
+	tests := []struct {
+		method, path, body string
+		status             int
+	}{
+		{"POST", "/scans", `{"Target": "127.0.0.1"}`, http.StatusServiceUnavailable},
+		{"POST", "/scans", `{"Target": "8.8.8.8"}`, http.StatusForbidden},
+		{"POST", "/scans", `{"Target": "127.0.0.1", "Options": {"Checks": ["ftp-anon"]}}`, http.StatusForbidden},
+		{"POST", "/scans", `{"Target": "127.0.0.1", "Unknown": true}`, http.StatusBadRequest},
+		{"POST", "/scans", `{`, http.StatusBadRequest},
+		{"GET", "/scans", "", http.StatusOK},
+		{"GET", "/scans/" + id, "", http.StatusOK},
+		{"GET", "/scans/" + id + "/results", "", http.StatusConflict},
+		{"GET", "/scans/unknown", "", http.StatusNotFound},
+		{"GET", "/other", "", http.StatusNotFound},
+		{"GET", "/scans/" + id + "/other", "", http.StatusNotFound},
+		{"PUT", "/scans/" + id, "", http.StatusMethodNotAllowed},
+		{"DELETE", "/scans/" + id, "", http.StatusAccepted},
+		{"DELETE", "/scans/" + id, "", http.StatusConflict},
+		{"DELETE", "/scans/unknown", "", http.StatusNotFound},
+		{"GET", "/scans/" + id + "/results", "", http.StatusOK},
+		{"GET", "/scans/" + id + "/results?format=xml", "", http.StatusOK},
+		{"GET", "/scans/" + id + "/results?format=csv", "", http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		if resp, v := do(tt.method, tt.path, "secret", tt.body); resp.StatusCode != tt.status {
+			t.Errorf("%s %s: status %d, want %d (%v)", tt.method, tt.path, resp.StatusCode, tt.status, v)
+		}
+	}
+
+	if _, job = do("GET", "/scans/"+id, "secret", ""); job["Status"] != string(JobCanceled) {
+		t.Errorf("status after cancel = %v", job["Status"])
+	}
+}
+
+func TestSchedulerNotFound(t *testing.T) {
+	s, err := NewScheduler(SchedulerOptions{Dir: t.TempDir()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	if err := s.Add(ScheduledScan{Name: "yearly", Schedule: "0 0 1 1 *", Target: "127.0.0.1"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Scan("yearly"); err != nil {
+		t.Errorf("Scan = %v", err)
+	}
+	if run, err := s.LastRun("yearly"); run != nil || err != nil {
+		t.Errorf("LastRun = %v, %v", run, err)
+	}
+
+	tests := []struct {
+		path   string
+		status int
+		body   string
+	}{
+		{"/schedules/yearly", http.StatusOK, `"Name":"yearly"`},
+		{"/schedules/yearly/last", http.StatusNotFound, "scan has not run yet"},
+		{"/schedules/unknown", http.StatusNotFound, ErrScheduleNotFound.Error()},
+		{"/schedules/unknown/last", http.StatusNotFound, ErrScheduleNotFound.Error()},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		s.ServeHTTP(w, httptest.NewRequest("GET", tt.path, nil))
+		if w.Code != tt.status || !strings.Contains(w.Body.String(), tt.body) {
+			t.Errorf("GET %s: status %d, %s", tt.path, w.Code, w.Body)
+		}
+	}
+
+	if err := s.Remove("yearly"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Scan("yearly"); err != ErrScheduleNotFound {
+		t.Errorf("Scan after Remove = %v", err)
+	}
+	if _, err := s.LastRun("yearly"); err != ErrScheduleNotFound {
+		t.Errorf("LastRun after Remove = %v", err)
+	}
+	if err := s.Remove("yearly"); err != ErrScheduleNotFound || errors.Is(err, ErrJobNotFound) {
+		t.Errorf("Remove twice = %v", err)
+	}
 }
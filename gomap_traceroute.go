@@ -0,0 +1,338 @@
+package gomap
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math/rand"
+	"net"
+	"time"
+
+	"github.com/JustinTimperio/gomap/packet"
+)
+
+// TraceHop is a single hop on the path to a host. IP is nil if the hop did not respond.
+type TraceHop struct {
+	TTL     int
+	IP      net.IP
+	RTT     time.Duration
+	Reached bool
+}
+
+// traceReply is a response to a TTL limited probe
+type traceReply struct {
+	ttl     int
+	ip      net.IP
+	at      time.Time
+	reached bool
+}
+
+// String with the ttl, round trip time and address of a hop
+func (h TraceHop) String() string {
+	if h.IP == nil {
+		return fmt.Sprintf("%d	*", h.TTL)
+	}
+	return fmt.Sprintf("%d	%s	%s", h.TTL, h.RTT.Round(10*time.Microsecond), h.IP)
+}
+
+// Traceroute finds the path to hostname using TTL limited probes. proto selects
+// "tcp", "udp" or "icmp" probes and port is the destination of tcp and udp probes.
+// Traceroute MUST be run as root/admin.
+func Traceroute(hostname string, proto string, port int, maxHops int) ([]TraceHop, error) {
+	dst, err := net.ResolveIPAddr("ip4", hostname)
+	if err != nil {
+		return nil, err
+	}
+	laddr, err := sourceAddress(dst.IP)
+	if err != nil {
+		return nil, err
+	}
+	if maxHops <= 0 {
+		maxHops = 30
+	}
+	return traceroute(laddr, hostname, proto, port, maxHops, 2*time.Second)
+}
+
+// tracePort picks the destination port for tracing a scanned host
+func tracePort(proto string, results []portResult) int {
+	if proto == "udp" {
+		return 33434
+	}
+	for _, r := range results {
+		if r.Status == PortOpen {
+			return r.Port
+		}
+	}
+	return 80
+}
+
+// traceroute sends a probe for every ttl up to maxHops at once then
+// collects the replies until timeout
+func traceroute(laddr string, raddr string, proto string, port int, maxHops int, timeout time.Duration) ([]TraceHop, error) {
+	dst, err := net.ResolveIPAddr("ip4", raddr)
+	if err != nil {
+		return nil, err
+	}
+	listenAddr, err := net.ResolveIPAddr("ip4", laddr)
+	if err != nil {
+		return nil, err
+	}
+
+	switch proto {
+	case "tcp", "udp", "icmp":
+	default:
+		return nil, fmt.Errorf("traceroute: unsupported protocol %q", proto)
+	}
+
+	// Every router on the path answers with an ICMP time exceeded
+	icmpConn, err := net.ListenIP("ip4:icmp", listenAddr)
+	if err != nil {
+		return nil, err
+	}
+	defer icmpConn.Close()
+
+	// Probes are told apart by source port for tcp and udp and by
+	// sequence number for icmp, each being offset by the ttl
+	base := uint16(random(10000, 65535-maxHops))
+	id := uint16(rand.Uint32())
+	deadline := time.Now().Add(timeout)
+	replies := make(chan traceReply, 2*maxHops)
+
+	// done stops the receivers once the replies are no longer collected
+	done := make(chan struct{})
+	defer close(done)
+
+	go recvTraceICMP(icmpConn, dst.IP, proto, base, id, maxHops, deadline, replies, done)
+	if proto == "tcp" {
+		tcpConn, err := net.ListenIP("ip4:tcp", listenAddr)
+		if err != nil {
+			return nil, err
+		}
+		defer tcpConn.Close()
+		go recvTraceTCP(tcpConn, dst.IP, base, maxHops, deadline, replies, done)
+	}
+
+	sent := make([]time.Time, maxHops+1)
+	for ttl := 1; ttl <= maxHops; ttl++ {
+		sent[ttl] = time.Now()
+		if err := sendTraceProbe(laddr, dst.IP, proto, port, ttl, base, id); err != nil {
+			return nil, err
+		}
+	}
+
+	hops := make([]TraceHop, maxHops)
+	for i := range hops {
+		hops[i].TTL = i + 1
+	}
+
+	timer := time.NewTimer(time.Until(deadline))
+	defer timer.Stop()
+collect:
+	for {
+		select {
+		case r := <-replies:
+			h := &hops[r.ttl-1]
+			if h.IP == nil {
+				h.IP = r.ip
+				h.RTT = r.at.Sub(sent[r.ttl])
+				h.Reached = r.reached
+			}
+		case <-timer.C:
+			break collect
+		}
+	}
+
+	return tracePath(hops), nil
+}
+
+// tracePath cuts hops at the first hop that reached the host or
+// after the last hop that responded at all
+func tracePath(hops []TraceHop) []TraceHop {
+	last := 0
+	for i, h := range hops {
+		if h.Reached {
+			return hops[:i+1]
+		}
+		if h.IP != nil {
+			last = i + 1
+		}
+	}
+	return hops[:last]
+}
+
+// sendTraceProbe sends a single probe to dst limited to ttl hops
+func sendTraceProbe(laddr string, dst net.IP, proto string, port int, ttl int, base uint16, id uint16) error {
+	var data []byte
+	var err error
+
+	src := net.ParseIP(laddr)
+	switch proto {
+	case "tcp":
+		tcp := packet.TCP{
+			SrcPort: base + uint16(ttl),
+			DstPort: uint16(port),
+			Seq:     rand.Uint32(),
+			Flags:   packet.TCPSyn,
+			Window:  8192,
+			Options: []packet.TCPOption{packet.MSSOption(1460)},
+		}
+		data, err = tcp.Marshal(src, dst)
+	case "udp":
+		udp := packet.UDP{
+			SrcPort: base + uint16(ttl),
+			DstPort: uint16(port),
+		}
+		data, err = udp.Marshal(src, dst)
+	default:
+		icmp := packet.ICMP{
+			Type: packet.ICMPEchoRequest,
+			Rest: uint32(id)<<16 | uint32(ttl),
+		}
+		data, err = icmp.Marshal()
+	}
+	if err != nil {
+		return err
+	}
+
+	conn, err := rawConn(src, dst, proto)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if err := setTTL(conn, ttl); err != nil {
+		return err
+	}
+	_, err = conn.Write(data)
+	return err
+}
+
+// recvTraceICMP collects time exceeded and unreachable errors quoting a
+// probe along with echo replies from dst
+func recvTraceICMP(conn *net.IPConn, dst net.IP, proto string, base uint16, id uint16, maxHops int, deadline time.Time, replies chan<- traceReply, done <-chan struct{}) {
+	conn.SetReadDeadline(deadline)
+
+	buff := make([]byte, 1500)
+	for {
+		n, addr, err := conn.ReadFrom(buff)
+		if err != nil {
+			if ne, ok := err.(net.Error); ok && ne.Timeout() {
+				return
+			}
+			select {
+			case <-done:
+				return
+			default:
+				continue
+			}
+		}
+		at := time.Now()
+		from := addr.(*net.IPAddr).IP
+
+		ttl, reached, ok := parseTraceICMP(buff[:n], from, dst, proto, base, id, maxHops)
+		if !ok {
+			continue
+		}
+		if !sendTraceReply(replies, done, traceReply{ttl: ttl, ip: from, at: at, reached: reached}) {
+			return
+		}
+	}
+}
+
+// parseTraceICMP returns the ttl of the probe an ICMP message from the address
+// from answers and if it came from dst itself. ok is false for unrelated messages.
+func parseTraceICMP(b []byte, from net.IP, dst net.IP, proto string, base uint16, id uint16, maxHops int) (ttl int, reached bool, ok bool) {
+	icmp, err := packet.ParseICMP(b)
+	if err != nil {
+		return 0, false, false
+	}
+
+	// Echo replies only come from the host itself
+	if icmp.Type == packet.ICMPEchoReply {
+		ttl = int(icmp.Seq())
+		if proto != "icmp" || icmp.ID() != id || !from.Equal(dst) || ttl < 1 || ttl > maxHops {
+			return 0, false, false
+		}
+		return ttl, true, true
+	}
+	if icmp.Type != packet.ICMPTimeExceeded && icmp.Type != packet.ICMPDestinationUnreachable {
+		return 0, false, false
+	}
+
+	// Errors quote the ip header and first 8 bytes of the probe
+	ip, err := packet.ParseIPv4(icmp.Payload)
+	if err != nil || !ip.Dst.Equal(dst) || len(ip.Payload) < 8 {
+		return 0, false, false
+	}
+
+	switch {
+	case proto == "icmp" && ip.Protocol == packet.ProtocolICMP:
+		if binary.BigEndian.Uint16(ip.Payload[4:6]) != id {
+			return 0, false, false
+		}
+		ttl = int(binary.BigEndian.Uint16(ip.Payload[6:8]))
+	case proto == "tcp" && ip.Protocol == packet.ProtocolTCP, proto == "udp" && ip.Protocol == packet.ProtocolUDP:
+		ttl = int(binary.BigEndian.Uint16(ip.Payload[0:2])) - int(base)
+	default:
+		return 0, false, false
+	}
+	if ttl < 1 || ttl > maxHops {
+		return 0, false, false
+	}
+	return ttl, icmp.Type == packet.ICMPDestinationUnreachable && from.Equal(dst), true
+}
+
+// recvTraceTCP collects SYN-ACK and RST replies from dst to tcp probes
+func recvTraceTCP(conn *net.IPConn, dst net.IP, base uint16, maxHops int, deadline time.Time, replies chan<- traceReply, done <-chan struct{}) {
+	conn.SetReadDeadline(deadline)
+
+	buff := make([]byte, 1500)
+	for {
+		n, addr, err := conn.ReadFrom(buff)
+		if err != nil {
+			if ne, ok := err.(net.Error); ok && ne.Timeout() {
+				return
+			}
+			select {
+			case <-done:
+				return
+			default:
+				continue
+			}
+		}
+		at := time.Now()
+		if !addr.(*net.IPAddr).IP.Equal(dst) {
+			continue
+		}
+
+		ttl, ok := parseTraceTCP(buff[:n], base, maxHops)
+		if !ok {
+			continue
+		}
+		if !sendTraceReply(replies, done, traceReply{ttl: ttl, ip: dst, at: at, reached: true}) {
+			return
+		}
+	}
+}
+
+// parseTraceTCP returns the ttl of the probe a tcp reply answers
+func parseTraceTCP(b []byte, base uint16, maxHops int) (int, bool) {
+	tcp, err := packet.ParseTCP(b)
+	if err != nil {
+		return 0, false
+	}
+	ttl := int(tcp.DstPort) - int(base)
+	if ttl < 1 || ttl > maxHops {
+		return 0, false
+	}
+	return ttl, true
+}
+
+// sendTraceReply passes r to the collector, it returns false once done is closed
+func sendTraceReply(replies chan<- traceReply, done <-chan struct{}, r traceReply) bool {
+	select {
+	case replies <- r:
+		return true
+	case <-done:
+		return false
+	}
+}
@@ -0,0 +1,606 @@
+package gomap
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// CPE is a Common Platform Enumeration name of a product
+type CPE struct {
+	// Part is "a" for applications, "o" for operating systems and "h" for hardware
+	Part    string
+	Vendor  string
+	Product string
+	Version string
+	Update  string
+}
+
+// ParseCPE parses a CPE in either the URI binding (cpe:/a:vendor:product:version)
+// or the 2.3 formatted string binding (cpe:2.3:a:vendor:product:version:...)
+func ParseCPE(s string) (CPE, error) {
+	var fields []string
+	switch {
+	case strings.HasPrefix(s, "cpe:2.3:"):
+		fields = splitCPE(strings.TrimPrefix(s, "cpe:2.3:"))
+	case strings.HasPrefix(s, "cpe:/"):
+		fields = strings.Split(strings.TrimPrefix(s, "cpe:/"), ":")
+	default:
+		return CPE{}, fmt.Errorf("invalid cpe %q", s)
+	}
+	if len(fields) < 3 || fields[0] == "" {
+		return CPE{}, fmt.Errorf("invalid cpe %q", s)
+	}
+
+	for len(fields) < 5 {
+		fields = append(fields, "")
+	}
+	c := CPE{
+		Part:    fields[0],
+		Vendor:  strings.ToLower(fields[1]),
+		Product: strings.ToLower(fields[2]),
+		Version: fields[3],
+		Update:  fields[4],
+	}
+	// Both bindings use an empty or * field for any value
+	if c.Version == "*" {
+		c.Version = ""
+	}
+	if c.Update == "*" {
+		c.Update = ""
+	}
+	return c, nil
+}
+
+// splitCPE splits the fields of a formatted string binding, which may
+// contain escaped colons
+func splitCPE(s string) []string {
+	var fields []string
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		switch {
+		case s[i] == '\\' && i+1 < len(s):
+			i++
+			b.WriteByte(s[i])
+		case s[i] == ':':
+			fields = append(fields, b.String())
+			b.Reset()
+		default:
+			b.WriteByte(s[i])
+		}
+	}
+	return append(fields, b.String())
+}
+
+// String with the CPE in the 2.3 formatted string binding
+func (c CPE) String() string {
+	field := func(v string) string {
+		if v == "" {
+			return "*"
+		}
+		return strings.ReplaceAll(v, ":", `\:`)
+	}
+	return fmt.Sprintf("cpe:2.3:%s:%s:%s:%s:%s:*:*:*:*:*:*", c.Part, field(c.Vendor), field(c.Product), field(c.Version), field(c.Update))
+}
+
+// CPEs returns the CPE names of the detected service. Names without a
+// version get the detected version and versions such as 8.9p1 are split
+// into a version and an update as NVD records them.
+func (v *ServiceVersion) CPEs() []CPE {
+	var cpes []CPE
+	for _, s := range v.CPE {
+		c, err := ParseCPE(s)
+		if err != nil {
+			continue
+		}
+		if c.Part == "a" && c.Version == "" && v.Version != "" {
+			c.Version = strings.Fields(v.Version)[0]
+		}
+		if c.Update == "" {
+			c.Version, c.Update = splitVersion(c.Version)
+		}
+		cpes = append(cpes, c)
+
+		for _, alias := range cpeAliases[c.Vendor+":"+c.Product] {
+			parts := strings.SplitN(alias, ":", 2)
+			a := c
+			a.Vendor, a.Product = parts[0], parts[1]
+			cpes = append(cpes, a)
+		}
+	}
+	return cpes
+}
+
+// cpeAliases lists other names NVD has used for a product, usually
+// after it changed hands
+var cpeAliases = map[string][]string{
+	"igor_sysoev:nginx":     {"f5:nginx", "nginx:nginx"},
+	"redislabs:redis":       {"redis:redis"},
+	"mysql:mysql":           {"oracle:mysql"},
+	"vsftpd_project:vsftpd": {"beasts:vsftpd"},
+	"elastic:elasticsearch": {"elasticsearch:elasticsearch"},
+	"mongodb:mongodb":       {"mongodb:mongodb_server"},
+}
+
+// splitVersion splits a trailing update such as p1 from a dotted version
+func splitVersion(v string) (string, string) {
+	i := 0
+	for i < len(v) && (v[i] == '.' || (v[i] >= '0' && v[i] <= '9')) {
+		i++
+	}
+	if i == 0 || i == len(v) {
+		return v, ""
+	}
+	update := strings.TrimLeft(v[i:], "-_")
+	for _, r := range update {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9') {
+			return v, ""
+		}
+	}
+	return strings.TrimSuffix(v[:i], "."), update
+}
+
+// Vulnerability is a known vulnerability of a detected product
+type Vulnerability struct {
+	ID          string
+	Severity    Severity
+	Score       float64 `json:",omitempty"`
+	Description string  `json:",omitempty"`
+	// CPE is the detected product the vulnerability was matched on
+	CPE string
+}
+
+// String with the id, severity and score of the vulnerability
+func (v Vulnerability) String() string {
+	if v.Score == 0 {
+		return fmt.Sprintf("%s (%s)", v.ID, v.Severity)
+	}
+	return fmt.Sprintf("%s (%s %.1f)", v.ID, v.Severity, v.Score)
+}
+
+// VulnDB holds the vulnerabilities of a local NVD JSON feed indexed by product
+type VulnDB struct {
+	mu      sync.RWMutex
+	entries map[string][]vulnEntry
+	count   int
+}
+
+// vulnEntry is one vulnerable product range of a vulnerability
+type vulnEntry struct {
+	id          string
+	severity    Severity
+	score       float64
+	description string
+
+	cpe          CPE
+	startInclude string
+	startExclude string
+	endInclude   string
+	endExclude   string
+}
+
+// NewVulnDB returns an empty vulnerability database
+func NewVulnDB() *VulnDB {
+	return &VulnDB{entries: make(map[string][]vulnEntry)}
+}
+
+// LoadVulnDB reads the NVD JSON feeds at paths, which may be gzipped,
+// into a single database
+func LoadVulnDB(paths ...string) (*VulnDB, error) {
+	db := NewVulnDB()
+	for _, path := range paths {
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		feed, err := ParseVulnFeed(f)
+		f.Close()
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", path, err)
+		}
+		db.Merge(feed)
+	}
+	return db, nil
+}
+
+// nvdFeed decodes both the 1.1 data feeds (CVE_Items) and the responses
+// of the 2.0 API (vulnerabilities)
+type nvdFeed struct {
+	Items []struct {
+		CVE struct {
+			Meta struct {
+				ID string `json:"ID"`
+			} `json:"CVE_data_meta"`
+			Description struct {
+				Data []nvdDescription `json:"description_data"`
+			} `json:"description"`
+		} `json:"cve"`
+		Configurations struct {
+			Nodes []nvdNode `json:"nodes"`
+		} `json:"configurations"`
+		Impact struct {
+			V3 struct {
+				CVSS nvdCVSS `json:"cvssV3"`
+			} `json:"baseMetricV3"`
+			V2 struct {
+				CVSS     nvdCVSS `json:"cvssV2"`
+				Severity string  `json:"severity"`
+			} `json:"baseMetricV2"`
+		} `json:"impact"`
+	} `json:"CVE_Items"`
+
+	Vulnerabilities []struct {
+		CVE struct {
+			ID             string           `json:"id"`
+			Descriptions   []nvdDescription `json:"descriptions"`
+			Configurations []struct {
+				Nodes []nvdNode `json:"nodes"`
+			} `json:"configurations"`
+			Metrics struct {
+				V31 []nvdMetric `json:"cvssMetricV31"`
+				V30 []nvdMetric `json:"cvssMetricV30"`
+				V2  []nvdMetric `json:"cvssMetricV2"`
+			} `json:"metrics"`
+		} `json:"cve"`
+	} `json:"vulnerabilities"`
+}
+
+type nvdDescription struct {
+	Lang  string `json:"lang"`
+	Value string `json:"value"`
+}
+
+type nvdCVSS struct {
+	BaseScore    float64 `json:"baseScore"`
+	BaseSeverity string  `json:"baseSeverity"`
+}
+
+type nvdMetric struct {
+	CVSS         nvdCVSS `json:"cvssData"`
+	BaseSeverity string  `json:"baseSeverity"`
+}
+
+// nvdNode is a node of a configuration. The 1.1 feeds nest nodes in
+// children and name the matches cpe_match, the 2.0 API uses cpeMatch.
+type nvdNode struct {
+	Children  []nvdNode     `json:"children"`
+	CPEMatch  []nvdCPEMatch `json:"cpe_match"`
+	CPEMatch2 []nvdCPEMatch `json:"cpeMatch"`
+}
+
+type nvdCPEMatch struct {
+	Vulnerable            bool   `json:"vulnerable"`
+	CPE23URI              string `json:"cpe23Uri"`
+	Criteria              string `json:"criteria"`
+	VersionStartIncluding string `json:"versionStartIncluding"`
+	VersionStartExcluding string `json:"versionStartExcluding"`
+	VersionEndIncluding   string `json:"versionEndIncluding"`
+	VersionEndExcluding   string `json:"versionEndExcluding"`
+}
+
+// ParseVulnFeed parses an NVD JSON feed. Configurations that require
+// several products together are matched on their vulnerable products
+// alone, so a vulnerability may be reported for a product that is only
+// affected on some platforms.
+func ParseVulnFeed(r io.Reader) (*VulnDB, error) {
+	br := bufio.NewReader(r)
+	if magic, err := br.Peek(2); err == nil && magic[0] == 0x1f && magic[1] == 0x8b {
+		gz, err := gzip.NewReader(br)
+		if err != nil {
+			return nil, err
+		}
+		defer gz.Close()
+		r = gz
+	} else {
+		r = br
+	}
+
+	var feed nvdFeed
+	if err := json.NewDecoder(r).Decode(&feed); err != nil {
+		return nil, err
+	}
+	if feed.Items == nil && feed.Vulnerabilities == nil {
+		return nil, errors.New("not an nvd json feed")
+	}
+
+	db := NewVulnDB()
+	for _, item := range feed.Items {
+		base := vulnEntry{
+			id:          item.CVE.Meta.ID,
+			description: englishDescription(item.CVE.Description.Data),
+		}
+		switch {
+		case item.Impact.V3.CVSS.BaseSeverity != "":
+			base.score = item.Impact.V3.CVSS.BaseScore
+			base.severity = nvdSeverity(item.Impact.V3.CVSS.BaseSeverity)
+		case item.Impact.V2.Severity != "":
+			base.score = item.Impact.V2.CVSS.BaseScore
+			base.severity = nvdSeverity(item.Impact.V2.Severity)
+		}
+		db.addNodes(base, item.Configurations.Nodes)
+	}
+
+	for _, v := range feed.Vulnerabilities {
+		base := vulnEntry{
+			id:          v.CVE.ID,
+			description: englishDescription(v.CVE.Descriptions),
+		}
+		for _, metrics := range [][]nvdMetric{v.CVE.Metrics.V31, v.CVE.Metrics.V30, v.CVE.Metrics.V2} {
+			if len(metrics) == 0 {
+				continue
+			}
+			m := metrics[0]
+			base.score = m.CVSS.BaseScore
+			if m.CVSS.BaseSeverity != "" {
+				base.severity = nvdSeverity(m.CVSS.BaseSeverity)
+			} else {
+				base.severity = nvdSeverity(m.BaseSeverity)
+			}
+			break
+		}
+		for _, c := range v.CVE.Configurations {
+			db.addNodes(base, c.Nodes)
+		}
+	}
+	return db, nil
+}
+
+// addNodes indexes the vulnerable products of nodes and their children
+func (db *VulnDB) addNodes(base vulnEntry, nodes []nvdNode) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	seen := make(map[string]bool)
+	var walk func([]nvdNode)
+	walk = func(nodes []nvdNode) {
+		for _, n := range nodes {
+			for _, m := range append(n.CPEMatch, n.CPEMatch2...) {
+				if !m.Vulnerable {
+					continue
+				}
+				name := m.Criteria
+				if name == "" {
+					name = m.CPE23URI
+				}
+				c, err := ParseCPE(name)
+				if err != nil {
+					continue
+				}
+
+				e := base
+				e.cpe = c
+				e.startInclude = m.VersionStartIncluding
+				e.startExclude = m.VersionStartExcluding
+				e.endInclude = m.VersionEndIncluding
+				e.endExclude = m.VersionEndExcluding
+				key := c.Vendor + ":" + c.Product
+				db.entries[key] = append(db.entries[key], e)
+				if !seen[e.id] {
+					seen[e.id] = true
+					db.count++
+				}
+			}
+			walk(n.Children)
+		}
+	}
+	walk(nodes)
+}
+
+// englishDescription returns the english description of a vulnerability
+func englishDescription(descs []nvdDescription) string {
+	for _, d := range descs {
+		if d.Lang == "en" {
+			return d.Value
+		}
+	}
+	return ""
+}
+
+// nvdSeverity converts an NVD severity to the severity of a finding
+func nvdSeverity(s string) Severity {
+	switch strings.ToUpper(s) {
+	case "LOW":
+		return SeverityLow
+	case "MEDIUM":
+		return SeverityMedium
+	case "HIGH":
+		return SeverityHigh
+	case "CRITICAL":
+		return SeverityCritical
+	}
+	return SeverityInfo
+}
+
+// Merge copies every vulnerability in other into db
+func (db *VulnDB) Merge(other *VulnDB) {
+	other.mu.RLock()
+	defer other.mu.RUnlock()
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	for key, entries := range other.entries {
+		db.entries[key] = append(db.entries[key], entries...)
+	}
+	db.count += other.count
+}
+
+// Len returns the number of vulnerabilities in the database
+func (db *VulnDB) Len() int {
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+	return db.count
+}
+
+// Match returns the vulnerabilities affecting the product named by cpe,
+// most severe first. Without a version only vulnerabilities affecting
+// every version of the product are returned.
+func (db *VulnDB) Match(cpe CPE) []Vulnerability {
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+
+	var vulns []Vulnerability
+	seen := make(map[string]bool)
+	for _, e := range db.entries[cpe.Vendor+":"+cpe.Product] {
+		if seen[e.id] || e.cpe.Part != cpe.Part || !e.affects(cpe) {
+			continue
+		}
+		seen[e.id] = true
+		vulns = append(vulns, Vulnerability{
+			ID:          e.id,
+			Severity:    e.severity,
+			Score:       e.score,
+			Description: e.description,
+			CPE:         cpe.String(),
+		})
+	}
+	sortVulnerabilities(vulns)
+	return vulns
+}
+
+// MatchVersion returns the vulnerabilities affecting any of the products
+// of a detected service
+func (db *VulnDB) MatchVersion(v *ServiceVersion) []Vulnerability {
+	var vulns []Vulnerability
+	seen := make(map[string]bool)
+	for _, c := range v.CPEs() {
+		for _, vuln := range db.Match(c) {
+			if !seen[vuln.ID] {
+				seen[vuln.ID] = true
+				vulns = append(vulns, vuln)
+			}
+		}
+	}
+	sortVulnerabilities(vulns)
+	return vulns
+}
+
+// affects reports if the entry covers the version of cpe
+func (e vulnEntry) affects(cpe CPE) bool {
+	ranged := e.startInclude != "" || e.startExclude != "" || e.endInclude != "" || e.endExclude != ""
+	fixed := e.cpe.Version != "" && e.cpe.Version != "-"
+	if cpe.Version == "" {
+		return !ranged && !fixed
+	}
+
+	if fixed {
+		if compareVersions(cpe.Version, e.cpe.Version) != 0 {
+			return false
+		}
+		// An unknown update matches any update
+		if e.cpe.Update != "" && e.cpe.Update != "-" && cpe.Update != "" && !strings.EqualFold(cpe.Update, e.cpe.Update) {
+			return false
+		}
+	}
+
+	switch {
+	case e.startInclude != "" && compareVersions(cpe.Version, e.startInclude) < 0:
+		return false
+	case e.startExclude != "" && compareVersions(cpe.Version, e.startExclude) <= 0:
+		return false
+	case e.endInclude != "" && compareVersions(cpe.Version, e.endInclude) > 0:
+		return false
+	case e.endExclude != "" && compareVersions(cpe.Version, e.endExclude) >= 0:
+		return false
+	}
+	return true
+}
+
+// compareVersions compares dotted versions part by part, numerically
+// where both parts are numbers. Missing parts count as 0 and letters
+// sort before numbers so 2.0rc1 comes before 2.0.
+func compareVersions(a, b string) int {
+	as, bs := versionParts(a), versionParts(b)
+	for i := 0; i < len(as) || i < len(bs); i++ {
+		x, y := "0", "0"
+		if i < len(as) {
+			x = as[i]
+		}
+		if i < len(bs) {
+			y = bs[i]
+		}
+
+		xNum, yNum := isDigit(x[0]), isDigit(y[0])
+		switch {
+		case xNum && yNum:
+			x, y = strings.TrimLeft(x, "0"), strings.TrimLeft(y, "0")
+			if len(x) != len(y) {
+				return compareInts(len(x), len(y))
+			}
+			if x != y {
+				return strings.Compare(x, y)
+			}
+		case xNum != yNum:
+			if xNum {
+				return 1
+			}
+			return -1
+		default:
+			if c := strings.Compare(strings.ToLower(x), strings.ToLower(y)); c != 0 {
+				return c
+			}
+		}
+	}
+	return 0
+}
+
+// versionParts splits a version into runs of digits and runs of letters
+func versionParts(v string) []string {
+	var parts []string
+	for i := 0; i < len(v); {
+		if !isAlnum(v[i]) {
+			i++
+			continue
+		}
+		j := i + 1
+		for j < len(v) && isAlnum(v[j]) && isDigit(v[j]) == isDigit(v[i]) {
+			j++
+		}
+		parts = append(parts, v[i:j])
+		i = j
+	}
+	return parts
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isAlnum(c byte) bool {
+	return isDigit(c) || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
+
+func compareInts(a, b int) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+// sortVulnerabilities orders vulnerabilities by score, most severe first
+func sortVulnerabilities(vulns []Vulnerability) {
+	sort.SliceStable(vulns, func(i, j int) bool {
+		if vulns[i].Score != vulns[j].Score {
+			return vulns[i].Score > vulns[j].Score
+		}
+		return vulns[i].ID < vulns[j].ID
+	})
+}
+
+// matchVulnerabilities records the vulnerabilities of the detected version of each port
+func matchVulnerabilities(results []portResult, db *VulnDB) {
+	for i, r := range results {
+		if r.Version != nil {
+			results[i].Vulns = db.MatchVersion(r.Version)
+		}
+	}
+}
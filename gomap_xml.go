@@ -0,0 +1,403 @@
+package gomap
+
+import (
+	"encoding/xml"
+	"fmt"
+	"sort"
+)
+
+// XmlRange is the root element of the XML results of a scan
+type XmlRange struct {
+	XMLName xml.Name  `xml:"gomaprun"`
+	Hosts   []XmlHost `xml:"host"`
+}
+
+// XmlHost contains the results of a single host
+type XmlHost struct {
+	Active    bool         `xml:"active,attr"`
+	Hostname  string       `xml:"hostname,attr"`
+	Addresses []XmlAddress `xml:"address"`
+	Names     []XmlName    `xml:"name,omitempty"`
+	Domains   []string     `xml:"domain,omitempty"`
+	Ports     []XmlPort    `xml:"port,omitempty"`
+	NetBIOS   *XmlNetBIOS  `xml:"netbios,omitempty"`
+	SMB       *XmlSMB      `xml:"smb,omitempty"`
+	SNMP      *XmlSNMP     `xml:"snmp,omitempty"`
+	UPnP      []XmlUPnP    `xml:"upnp,omitempty"`
+	MDNS      []XmlMDNS    `xml:"mdns,omitempty"`
+	Findings  []XmlFinding `xml:"finding,omitempty"`
+	OS        []XmlOSMatch `xml:"osmatch,omitempty"`
+	Trace     []string     `xml:"hop,omitempty"`
+}
+
+// XmlAddress is an address of a host with its open ports and what was
+// found about it when every address of a hostname was scanned
+type XmlAddress struct {
+	Addr     string       `xml:"addr,attr"`
+	Ports    []XmlPort    `xml:"port,omitempty"`
+	NetBIOS  *XmlNetBIOS  `xml:"netbios,omitempty"`
+	SMB      *XmlSMB      `xml:"smb,omitempty"`
+	SNMP     *XmlSNMP     `xml:"snmp,omitempty"`
+	Findings []XmlFinding `xml:"finding,omitempty"`
+	OS       []XmlOSMatch `xml:"osmatch,omitempty"`
+	Trace    []string     `xml:"hop,omitempty"`
+}
+
+// XmlName is a name found by the reverse lookup of a host
+type XmlName struct {
+	Name      string `xml:",chardata"`
+	Confirmed bool   `xml:"confirmed,attr"`
+}
+
+// XmlPort contains the result of a single port
+type XmlPort struct {
+	Port    int         `xml:"portid,attr"`
+	State   PortState   `xml:"state,attr"`
+	Service string      `xml:"service,attr"`
+	Version *XmlVersion `xml:"version,omitempty"`
+	Vulns   []XmlVuln   `xml:"vuln,omitempty"`
+	SSH     *XmlSSH     `xml:"ssh,omitempty"`
+}
+
+// XmlVersion contains the detected version of a service
+type XmlVersion struct {
+	Product    string   `xml:"product,attr,omitempty"`
+	Version    string   `xml:"version,attr,omitempty"`
+	Info       string   `xml:"extrainfo,attr,omitempty"`
+	OS         string   `xml:"ostype,attr,omitempty"`
+	DeviceType string   `xml:"devicetype,attr,omitempty"`
+	Tunnel     string   `xml:"tunnel,attr,omitempty"`
+	CPE        []string `xml:"cpe,omitempty"`
+}
+
+// XmlVuln is a vulnerability matched on a port
+type XmlVuln struct {
+	ID       string   `xml:"id,attr"`
+	Severity Severity `xml:"severity,attr"`
+	Score    float64  `xml:"score,attr,omitempty"`
+	CPE      string   `xml:"cpe,attr"`
+}
+
+// XmlFinding is a finding of a check
+type XmlFinding struct {
+	Check    string    `xml:"check,attr"`
+	Port     int       `xml:"port,attr,omitempty"`
+	Severity Severity  `xml:"severity,attr"`
+	Title    string    `xml:"title"`
+	Detail   string    `xml:"detail,omitempty"`
+	Data     []XmlData `xml:"data,omitempty"`
+}
+
+// XmlData is a value a check recorded with its finding
+type XmlData struct {
+	Key   string `xml:"key,attr"`
+	Value string `xml:",chardata"`
+}
+
+// XmlSSH is what the key exchange of an SSH server offered
+type XmlSSH struct {
+	Banner            string      `xml:"banner,attr"`
+	KexAlgorithms     []string    `xml:"kex,omitempty"`
+	HostKeyAlgorithms []string    `xml:"hostkeyalgorithm,omitempty"`
+	Ciphers           []string    `xml:"cipher,omitempty"`
+	MACs              []string    `xml:"mac,omitempty"`
+	Compression       []string    `xml:"compression,omitempty"`
+	HostKeys          []XmlSSHKey `xml:"hostkey,omitempty"`
+	Deprecated        []string    `xml:"deprecated,omitempty"`
+}
+
+// XmlSSHKey is a host key of an SSH server
+type XmlSSHKey struct {
+	Type        string `xml:"type,attr"`
+	Fingerprint string `xml:"fingerprint,attr"`
+}
+
+// XmlNetBIOS is the answer to a NetBIOS node status query
+type XmlNetBIOS struct {
+	Name      string           `xml:"name,attr"`
+	Workgroup string           `xml:"workgroup,attr,omitempty"`
+	MAC       string           `xml:"mac,attr,omitempty"`
+	Names     []XmlNetBIOSName `xml:"name,omitempty"`
+}
+
+// XmlNetBIOSName is a name registered by a host, Suffix in hex
+type XmlNetBIOSName struct {
+	Name   string `xml:",chardata"`
+	Suffix string `xml:"suffix,attr"`
+	Group  bool   `xml:"group,attr"`
+}
+
+// XmlSMB is what an SMB server agreed to during negotiation
+type XmlSMB struct {
+	SigningRequired bool     `xml:"signingrequired,attr"`
+	Dialects        []string `xml:"dialect,omitempty"`
+}
+
+// XmlSNMP is the system information and interface table read over SNMP,
+// Uptime in seconds
+type XmlSNMP struct {
+	Version    string             `xml:"version,attr"`
+	SysName    string             `xml:"sysname,attr"`
+	ObjectID   string             `xml:"objectid,attr,omitempty"`
+	Uptime     int64              `xml:"uptime,attr"`
+	SysDescr   string             `xml:"descr,omitempty"`
+	Interfaces []XmlSNMPInterface `xml:"interface,omitempty"`
+}
+
+// XmlSNMPInterface is an entry of the interface table, Speed in bits per second
+type XmlSNMPInterface struct {
+	Index       int    `xml:"index,attr"`
+	Descr       string `xml:"descr,attr"`
+	Type        int    `xml:"type,attr"`
+	MTU         int    `xml:"mtu,attr"`
+	Speed       uint64 `xml:"speed,attr"`
+	PhysAddress string `xml:"physaddress,attr,omitempty"`
+	AdminStatus int    `xml:"adminstatus,attr"`
+	OperStatus  int    `xml:"operstatus,attr"`
+}
+
+// XmlUPnP is a UPnP device found over SSDP
+type XmlUPnP struct {
+	Location     string   `xml:"location,attr"`
+	Server       string   `xml:"server,attr,omitempty"`
+	USN          string   `xml:"usn,attr,omitempty"`
+	FriendlyName string   `xml:"friendlyname,attr,omitempty"`
+	DeviceType   string   `xml:"devicetype,attr,omitempty"`
+	Manufacturer string   `xml:"manufacturer,attr,omitempty"`
+	ModelName    string   `xml:"modelname,attr,omitempty"`
+	ModelNumber  string   `xml:"modelnumber,attr,omitempty"`
+	Services     []string `xml:"service,omitempty"`
+}
+
+// XmlMDNS is a service announced over multicast DNS
+type XmlMDNS struct {
+	Instance string   `xml:"instance,attr"`
+	Service  string   `xml:"service,attr"`
+	Host     string   `xml:"host,attr"`
+	Port     int      `xml:"port,attr"`
+	Text     []string `xml:"txt,omitempty"`
+}
+
+// XmlOSMatch is a guess of the operating system of a host
+type XmlOSMatch struct {
+	Family   string `xml:"family,attr"`
+	Version  string `xml:"version,attr"`
+	Class    string `xml:"class,attr"`
+	Accuracy int    `xml:"accuracy,attr"`
+}
+
+// Contains a marshaled document containing the results for a ip scan
+func (results *IPScanResult) Xml() (string, error) {
+	return RangeScanResult{results}.Xml()
+}
+
+// Contains a marshaled document containing the results for a range scan
+func (results RangeScanResult) Xml() (string, error) {
+	var data XmlRange
+	for _, r := range results {
+		data.Hosts = append(data.Hosts, r.xmlHost())
+	}
+
+	x, err := xml.MarshalIndent(data, "", "	")
+	if err != nil {
+		return "", err
+	}
+	return xml.Header + string(x), nil
+}
+
+// xmlHost converts the results of a single scanned IP to an XML element
+func (results *IPScanResult) xmlHost() XmlHost {
+	host := XmlHost{
+		Active:   results.active(),
+		Hostname: results.Hostname,
+		Domains:  results.Domains,
+		Ports:    xmlPorts(results.Results),
+		NetBIOS:  xmlNetBIOS(results.NetBIOS),
+		SMB:      xmlSMB(results.SMB),
+		SNMP:     xmlSNMP(results.SNMP),
+		Findings: xmlFindings(results.Findings),
+		OS:       xmlOS(results.OS),
+		Trace:    xmlTrace(results.Trace),
+	}
+
+	if len(results.Addresses) > 0 {
+		for _, a := range results.Addresses {
+			host.Addresses = append(host.Addresses, XmlAddress{
+				Addr:     a.IP.String(),
+				Ports:    xmlPorts(a.Results),
+				NetBIOS:  xmlNetBIOS(a.NetBIOS),
+				SMB:      xmlSMB(a.SMB),
+				SNMP:     xmlSNMP(a.SNMP),
+				Findings: xmlFindings(a.Findings),
+				OS:       xmlOS(a.OS),
+				Trace:    xmlTrace(a.Trace),
+			})
+		}
+	} else if ip := results.address(); ip != nil {
+		host.Addresses = []XmlAddress{{Addr: ip.String()}}
+	}
+
+	for _, n := range results.Names {
+		host.Names = append(host.Names, XmlName(n))
+	}
+	for _, d := range results.UPnP {
+		host.UPnP = append(host.UPnP, XmlUPnP{
+			Location:     d.Location,
+			Server:       d.Server,
+			USN:          d.USN,
+			FriendlyName: d.FriendlyName,
+			DeviceType:   d.DeviceType,
+			Manufacturer: d.Manufacturer,
+			ModelName:    d.ModelName,
+			ModelNumber:  d.ModelNumber,
+			Services:     d.Services,
+		})
+	}
+	for _, m := range results.MDNS {
+		host.MDNS = append(host.MDNS, XmlMDNS{Instance: m.Instance, Service: m.Service, Host: m.Host, Port: m.Port, Text: m.Text})
+	}
+	return host
+}
+
+// xmlFindings converts findings to XML elements with their data sorted by key
+func xmlFindings(findings []Finding) []XmlFinding {
+	var xf []XmlFinding
+	for _, f := range findings {
+		x := XmlFinding{
+			Check:    f.Check,
+			Port:     f.Port,
+			Severity: f.Severity,
+			Title:    f.Title,
+			Detail:   f.Detail,
+		}
+		for k, v := range f.Data {
+			x.Data = append(x.Data, XmlData{Key: k, Value: v})
+		}
+		sort.Slice(x.Data, func(i, j int) bool {
+			return x.Data[i].Key < x.Data[j].Key
+		})
+		xf = append(xf, x)
+	}
+	return xf
+}
+
+// xmlOS converts operating system guesses to XML elements
+func xmlOS(matches []OSMatch) []XmlOSMatch {
+	var xm []XmlOSMatch
+	for _, m := range matches {
+		xm = append(xm, XmlOSMatch(m))
+	}
+	return xm
+}
+
+// xmlTrace converts the hops of a traceroute to XML elements
+func xmlTrace(hops []TraceHop) []string {
+	var xh []string
+	for _, h := range hops {
+		xh = append(xh, h.String())
+	}
+	return xh
+}
+
+// xmlNetBIOS converts a node status answer to an XML element
+func xmlNetBIOS(n *NetBIOSInfo) *XmlNetBIOS {
+	if n == nil {
+		return nil
+	}
+	x := &XmlNetBIOS{Name: n.Name, Workgroup: n.Workgroup}
+	if n.MAC != nil {
+		x.MAC = n.MAC.String()
+	}
+	for _, name := range n.Names {
+		x.Names = append(x.Names, XmlNetBIOSName{Name: name.Name, Suffix: fmt.Sprintf("%02x", name.Suffix), Group: name.Group})
+	}
+	return x
+}
+
+// xmlSMB converts an SMB negotiation to an XML element
+func xmlSMB(s *SMBInfo) *XmlSMB {
+	if s == nil {
+		return nil
+	}
+	return &XmlSMB{SigningRequired: s.SigningRequired, Dialects: s.Dialects}
+}
+
+// xmlSNMP converts what was read over SNMP to an XML element
+func xmlSNMP(s *SNMPInfo) *XmlSNMP {
+	if s == nil {
+		return nil
+	}
+	x := &XmlSNMP{
+		Version:  s.Version,
+		SysName:  s.SysName,
+		ObjectID: s.SysObjectID,
+		Uptime:   int64(s.SysUpTime.Seconds()),
+		SysDescr: s.SysDescr,
+	}
+	for _, i := range s.Interfaces {
+		xi := XmlSNMPInterface{
+			Index:       i.Index,
+			Descr:       i.Descr,
+			Type:        i.Type,
+			MTU:         i.MTU,
+			Speed:       i.Speed,
+			AdminStatus: i.AdminStatus,
+			OperStatus:  i.OperStatus,
+		}
+		if len(i.PhysAddress) > 0 {
+			xi.PhysAddress = i.PhysAddress.String()
+		}
+		x.Interfaces = append(x.Interfaces, xi)
+	}
+	return x
+}
+
+// xmlSSH converts an SSH key exchange to an XML element
+func xmlSSH(s *SSHInfo) *XmlSSH {
+	if s == nil {
+		return nil
+	}
+	x := &XmlSSH{
+		Banner:            s.Banner,
+		KexAlgorithms:     s.KexAlgorithms,
+		HostKeyAlgorithms: s.HostKeyAlgorithms,
+		Ciphers:           s.Ciphers,
+		MACs:              s.MACs,
+		Compression:       s.Compression,
+		Deprecated:        s.Deprecated,
+	}
+	for _, k := range s.HostKeys {
+		x.HostKeys = append(x.HostKeys, XmlSSHKey(k))
+	}
+	return x
+}
+
+// xmlPorts converts the open ports of results to XML elements
+func xmlPorts(results []portResult) []XmlPort {
+	var ports []XmlPort
+	for _, r := range results {
+		if !r.State {
+			continue
+		}
+		p := XmlPort{Port: r.Port, State: r.Status, Service: r.Service, SSH: xmlSSH(r.SSH)}
+		if p.State == "" {
+			p.State = PortOpen
+		}
+		if v := r.Version; v != nil {
+			p.Version = &XmlVersion{
+				Product:    v.Product,
+				Version:    v.Version,
+				Info:       v.Info,
+				OS:         v.OS,
+				DeviceType: v.DeviceType,
+				Tunnel:     v.Tunnel,
+				CPE:        v.CPE,
+			}
+		}
+		for _, v := range r.Vulns {
+			p.Vulns = append(p.Vulns, XmlVuln{ID: v.ID, Severity: v.Severity, Score: v.Score, CPE: v.CPE})
+		}
+		ports = append(ports, p)
+	}
+	return ports
+}
@@ -0,0 +1,280 @@
+package packet
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+)
+
+// DNS record types
+const (
+	DNSTypeA     = 1
+	DNSTypeNS    = 2
+	DNSTypeCNAME = 5
+	DNSTypeSOA   = 6
+	DNSTypePTR   = 12
+	DNSTypeTXT   = 16
+	DNSTypeAAAA  = 28
+	DNSTypeSRV   = 33
+	DNSTypeAXFR  = 252
+	DNSTypeANY   = 255
+)
+
+// DNSClassINET is the internet class. mDNS uses the top bit of the
+// class to request unicast replies and to flush caches.
+const DNSClassINET = 1
+
+// DNS header flags
+const (
+	DNSResponse         = 1 << 15
+	DNSAuthoritative    = 1 << 10
+	DNSTruncated        = 1 << 9
+	DNSRecursionDesired = 1 << 8
+)
+
+// ErrDNSName is returned for a malformed or looping compressed name
+var ErrDNSName = errors.New("packet: invalid dns name")
+
+// DNS is a DNS message
+type DNS struct {
+	ID          uint16
+	Flags       uint16
+	Questions   []DNSQuestion
+	Answers     []DNSResource
+	Authorities []DNSResource
+	Additionals []DNSResource
+}
+
+// DNSQuestion is an entry of the question section
+type DNSQuestion struct {
+	Name  string
+	Type  uint16
+	Class uint16
+}
+
+// DNSResource is a resource record. Data holds the raw record data,
+// the other fields are decoded from it for the record types gomap uses.
+type DNSResource struct {
+	Name  string
+	Type  uint16
+	Class uint16
+	TTL   uint32
+	Data  []byte
+
+	// IP is set for A and AAAA records
+	IP net.IP
+	// Target is the name of NS, CNAME, PTR and SRV records
+	// and the primary server of SOA records
+	Target string
+	// Text holds the strings of TXT records
+	Text []string
+	// Priority, Weight and Port are set for SRV records
+	Priority uint16
+	Weight   uint16
+	Port     uint16
+}
+
+// Rcode returns the response code of the message
+func (d *DNS) Rcode() int {
+	return int(d.Flags & 0xf)
+}
+
+// Marshal returns the message in wire format. Names are not compressed
+// and resources are written from their Data field.
+func (d *DNS) Marshal() ([]byte, error) {
+	b := make([]byte, 12)
+	binary.BigEndian.PutUint16(b[0:2], d.ID)
+	binary.BigEndian.PutUint16(b[2:4], d.Flags)
+	binary.BigEndian.PutUint16(b[4:6], uint16(len(d.Questions)))
+	binary.BigEndian.PutUint16(b[6:8], uint16(len(d.Answers)))
+	binary.BigEndian.PutUint16(b[8:10], uint16(len(d.Authorities)))
+	binary.BigEndian.PutUint16(b[10:12], uint16(len(d.Additionals)))
+
+	var err error
+	for _, q := range d.Questions {
+		if b, err = appendName(b, q.Name); err != nil {
+			return nil, err
+		}
+		b = appendUint16(b, q.Type)
+		b = appendUint16(b, q.Class)
+	}
+
+	for _, section := range [][]DNSResource{d.Answers, d.Authorities, d.Additionals} {
+		for _, r := range section {
+			if b, err = appendName(b, r.Name); err != nil {
+				return nil, err
+			}
+			b = appendUint16(b, r.Type)
+			b = appendUint16(b, r.Class)
+			b = append(b, byte(r.TTL>>24), byte(r.TTL>>16), byte(r.TTL>>8), byte(r.TTL))
+			b = appendUint16(b, uint16(len(r.Data)))
+			b = append(b, r.Data...)
+		}
+	}
+	return b, nil
+}
+
+// ParseDNS decodes a DNS message
+func ParseDNS(b []byte) (*DNS, error) {
+	if len(b) < 12 {
+		return nil, ErrTruncated
+	}
+
+	d := &DNS{
+		ID:    binary.BigEndian.Uint16(b[0:2]),
+		Flags: binary.BigEndian.Uint16(b[2:4]),
+	}
+	counts := []int{
+		int(binary.BigEndian.Uint16(b[4:6])),
+		int(binary.BigEndian.Uint16(b[6:8])),
+		int(binary.BigEndian.Uint16(b[8:10])),
+		int(binary.BigEndian.Uint16(b[10:12])),
+	}
+
+	off := 12
+	for i := 0; i < counts[0]; i++ {
+		name, n, err := readName(b, off)
+		if err != nil {
+			return nil, err
+		}
+		off = n
+		if off+4 > len(b) {
+			return nil, ErrTruncated
+		}
+		d.Questions = append(d.Questions, DNSQuestion{
+			Name:  name,
+			Type:  binary.BigEndian.Uint16(b[off : off+2]),
+			Class: binary.BigEndian.Uint16(b[off+2 : off+4]),
+		})
+		off += 4
+	}
+
+	sections := []*[]DNSResource{&d.Answers, &d.Authorities, &d.Additionals}
+	for i, section := range sections {
+		for j := 0; j < counts[i+1]; j++ {
+			r, n, err := readResource(b, off)
+			if err != nil {
+				return nil, err
+			}
+			off = n
+			*section = append(*section, r)
+		}
+	}
+	return d, nil
+}
+
+// readResource decodes the resource record starting at off
+func readResource(b []byte, off int) (DNSResource, int, error) {
+	var r DNSResource
+	name, off, err := readName(b, off)
+	if err != nil {
+		return r, 0, err
+	}
+	if off+10 > len(b) {
+		return r, 0, ErrTruncated
+	}
+
+	r.Name = name
+	r.Type = binary.BigEndian.Uint16(b[off : off+2])
+	r.Class = binary.BigEndian.Uint16(b[off+2 : off+4])
+	r.TTL = binary.BigEndian.Uint32(b[off+4 : off+8])
+	length := int(binary.BigEndian.Uint16(b[off+8 : off+10]))
+	off += 10
+	if off+length > len(b) {
+		return r, 0, ErrTruncated
+	}
+	r.Data = b[off : off+length]
+
+	// Names inside the data may point back into the rest of the message
+	switch r.Type {
+	case DNSTypeA, DNSTypeAAAA:
+		if length == 4 || length == 16 {
+			r.IP = net.IP(r.Data)
+		}
+	case DNSTypeNS, DNSTypeCNAME, DNSTypePTR, DNSTypeSOA:
+		if r.Target, _, err = readName(b, off); err != nil {
+			return r, 0, err
+		}
+	case DNSTypeSRV:
+		if length < 7 {
+			return r, 0, ErrTruncated
+		}
+		r.Priority = binary.BigEndian.Uint16(r.Data[0:2])
+		r.Weight = binary.BigEndian.Uint16(r.Data[2:4])
+		r.Port = binary.BigEndian.Uint16(r.Data[4:6])
+		if r.Target, _, err = readName(b, off+6); err != nil {
+			return r, 0, err
+		}
+	case DNSTypeTXT:
+		for i := 0; i < length; {
+			l := int(r.Data[i])
+			if i+1+l > length {
+				return r, 0, ErrTruncated
+			}
+			r.Text = append(r.Text, string(r.Data[i+1:i+1+l]))
+			i += 1 + l
+		}
+	}
+	return r, off + length, nil
+}
+
+// readName decodes the possibly compressed name at off and returns
+// it with the offset of the data following it
+func readName(b []byte, off int) (string, int, error) {
+	var labels []string
+	end := -1
+	for jumps := 0; ; {
+		if off >= len(b) {
+			return "", 0, ErrTruncated
+		}
+		l := int(b[off])
+		switch {
+		case l == 0:
+			if end < 0 {
+				end = off + 1
+			}
+			return strings.Join(labels, ".") + ".", end, nil
+		case l&0xc0 == 0xc0:
+			if off+1 >= len(b) {
+				return "", 0, ErrTruncated
+			}
+			if end < 0 {
+				end = off + 2
+			}
+			jumps++
+			if jumps > 32 {
+				return "", 0, ErrDNSName
+			}
+			off = int(binary.BigEndian.Uint16(b[off:off+2]) & 0x3fff)
+		case l&0xc0 != 0:
+			return "", 0, ErrDNSName
+		default:
+			if off+1+l > len(b) {
+				return "", 0, ErrTruncated
+			}
+			labels = append(labels, string(b[off+1:off+1+l]))
+			off += 1 + l
+		}
+	}
+}
+
+// appendName appends name in uncompressed wire format
+func appendName(b []byte, name string) ([]byte, error) {
+	name = strings.TrimSuffix(name, ".")
+	if name != "" {
+		for _, label := range strings.Split(name, ".") {
+			if len(label) == 0 || len(label) > 63 {
+				return nil, fmt.Errorf("packet: invalid dns label %q", label)
+			}
+			b = append(b, byte(len(label)))
+			b = append(b, label...)
+		}
+	}
+	return append(b, 0), nil
+}
+
+func appendUint16(b []byte, v uint16) []byte {
+	return append(b, byte(v>>8), byte(v))
+}
@@ -0,0 +1,84 @@
+package packet
+
+import (
+	"encoding/binary"
+	"net"
+)
+
+// ICMP message types
+const (
+	ICMPEchoReply              = 0
+	ICMPDestinationUnreachable = 3
+	ICMPEchoRequest            = 8
+	ICMPTimeExceeded           = 11
+	ICMPTimestampRequest       = 13
+	ICMPTimestampReply         = 14
+
+	ICMPv6DestinationUnreachable = 1
+	ICMPv6PacketTooBig           = 2
+	ICMPv6TimeExceeded           = 3
+	ICMPv6EchoRequest            = 128
+	ICMPv6EchoReply              = 129
+)
+
+// ICMP is an ICMP or ICMPv6 header. Rest holds the four bytes following the
+// checksum which contain the identifier and sequence of echo messages.
+type ICMP struct {
+	Type     uint8
+	Code     uint8
+	Checksum uint16
+	Rest     uint32
+	Payload  []byte
+}
+
+// ID returns the identifier of an echo message
+func (m *ICMP) ID() uint16 {
+	return uint16(m.Rest >> 16)
+}
+
+// Seq returns the sequence number of an echo message
+func (m *ICMP) Seq() uint16 {
+	return uint16(m.Rest)
+}
+
+// Marshal sets the checksum field and returns an ICMP message
+func (m *ICMP) Marshal() ([]byte, error) {
+	b := m.header()
+	m.Checksum = Checksum(b)
+	binary.BigEndian.PutUint16(b[2:4], m.Checksum)
+	return b, nil
+}
+
+// MarshalIPv6 sets the checksum field and returns an ICMPv6 message.
+// src and dst are the addresses used for the checksum pseudo header.
+func (m *ICMP) MarshalIPv6(src, dst net.IP) ([]byte, error) {
+	b := m.header()
+	m.Checksum = TransportChecksum(ProtocolICMPv6, src, dst, b)
+	binary.BigEndian.PutUint16(b[2:4], m.Checksum)
+	return b, nil
+}
+
+// header returns the message with a zero checksum
+func (m *ICMP) header() []byte {
+	b := make([]byte, 8, 8+len(m.Payload))
+	b[0] = m.Type
+	b[1] = m.Code
+	binary.BigEndian.PutUint32(b[4:8], m.Rest)
+	return append(b, m.Payload...)
+}
+
+// ParseICMP decodes an ICMP or ICMPv6 header. For error messages the
+// payload holds the start of the packet that caused the error.
+func ParseICMP(b []byte) (*ICMP, error) {
+	if len(b) < 8 {
+		return nil, ErrTruncated
+	}
+
+	return &ICMP{
+		Type:     b[0],
+		Code:     b[1],
+		Checksum: binary.BigEndian.Uint16(b[2:4]),
+		Rest:     binary.BigEndian.Uint32(b[4:8]),
+		Payload:  b[8:],
+	}, nil
+}
@@ -0,0 +1,170 @@
+package packet
+
+import (
+	"encoding/binary"
+	"fmt"
+	"net"
+)
+
+// IPv4 header flags
+const (
+	IPv4MoreFragments = 0x1
+	IPv4DontFragment  = 0x2
+)
+
+// IPv4 is an IPv4 header
+type IPv4 struct {
+	TOS        uint8
+	Length     uint16
+	ID         uint16
+	Flags      uint8
+	FragOffset uint16
+	TTL        uint8
+	Protocol   uint8
+	Checksum   uint16
+	Src        net.IP
+	Dst        net.IP
+	Options    []byte
+	Payload    []byte
+}
+
+// HeaderLen returns the length of the header including padded options
+func (h *IPv4) HeaderLen() int {
+	return 20 + (len(h.Options)+3)/4*4
+}
+
+// Marshal sets the length and checksum fields and returns the header and payload
+func (h *IPv4) Marshal() ([]byte, error) {
+	src, dst := h.Src.To4(), h.Dst.To4()
+	if src == nil || dst == nil {
+		return nil, fmt.Errorf("packet: ipv4 header requires ipv4 addresses")
+	}
+
+	hlen := h.HeaderLen()
+	if hlen > 60 {
+		return nil, fmt.Errorf("packet: ipv4 options too long (%d bytes)", len(h.Options))
+	}
+	if hlen+len(h.Payload) > 0xffff {
+		return nil, fmt.Errorf("packet: ipv4 packet too long (%d bytes)", hlen+len(h.Payload))
+	}
+	h.Length = uint16(hlen + len(h.Payload))
+
+	b := make([]byte, hlen, int(h.Length))
+	b[0] = 4<<4 | uint8(hlen/4)
+	b[1] = h.TOS
+	binary.BigEndian.PutUint16(b[2:4], h.Length)
+	binary.BigEndian.PutUint16(b[4:6], h.ID)
+	binary.BigEndian.PutUint16(b[6:8], uint16(h.Flags)<<13|h.FragOffset&0x1fff)
+	b[8] = h.TTL
+	b[9] = h.Protocol
+	copy(b[12:16], src)
+	copy(b[16:20], dst)
+	copy(b[20:], h.Options)
+
+	h.Checksum = Checksum(b)
+	binary.BigEndian.PutUint16(b[10:12], h.Checksum)
+	return append(b, h.Payload...), nil
+}
+
+// ParseIPv4 decodes an IPv4 header. The payload is cut to the total length
+// in the header but may be shorter if b is truncated, as it is when quoted
+// inside an ICMP error.
+func ParseIPv4(b []byte) (*IPv4, error) {
+	if len(b) < 20 {
+		return nil, ErrTruncated
+	}
+	if b[0]>>4 != 4 {
+		return nil, fmt.Errorf("packet: not an ipv4 header (version %d)", b[0]>>4)
+	}
+
+	hlen := int(b[0]&0x0f) * 4
+	if hlen < 20 {
+		return nil, fmt.Errorf("packet: invalid ipv4 header length %d", hlen)
+	}
+	if len(b) < hlen {
+		return nil, ErrTruncated
+	}
+
+	h := &IPv4{
+		TOS:        b[1],
+		Length:     binary.BigEndian.Uint16(b[2:4]),
+		ID:         binary.BigEndian.Uint16(b[4:6]),
+		Flags:      b[6] >> 5,
+		FragOffset: binary.BigEndian.Uint16(b[6:8]) & 0x1fff,
+		TTL:        b[8],
+		Protocol:   b[9],
+		Checksum:   binary.BigEndian.Uint16(b[10:12]),
+		Src:        net.IP(append([]byte(nil), b[12:16]...)),
+		Dst:        net.IP(append([]byte(nil), b[16:20]...)),
+	}
+	if hlen > 20 {
+		h.Options = b[20:hlen]
+	}
+
+	end := int(h.Length)
+	if end < hlen || end > len(b) {
+		end = len(b)
+	}
+	h.Payload = b[hlen:end]
+	return h, nil
+}
+
+// IPv6 is an IPv6 header. Extension headers are left in the payload.
+type IPv6 struct {
+	TrafficClass  uint8
+	FlowLabel     uint32
+	PayloadLength uint16
+	NextHeader    uint8
+	HopLimit      uint8
+	Src           net.IP
+	Dst           net.IP
+	Payload       []byte
+}
+
+// Marshal sets the payload length and returns the header and payload
+func (h *IPv6) Marshal() ([]byte, error) {
+	if h.Src.To16() == nil || h.Dst.To16() == nil {
+		return nil, fmt.Errorf("packet: ipv6 header requires ipv6 addresses")
+	}
+	if len(h.Payload) > 0xffff {
+		return nil, fmt.Errorf("packet: ipv6 payload too long (%d bytes)", len(h.Payload))
+	}
+	h.PayloadLength = uint16(len(h.Payload))
+
+	b := make([]byte, 40, 40+len(h.Payload))
+	binary.BigEndian.PutUint32(b[0:4], 6<<28|uint32(h.TrafficClass)<<20|h.FlowLabel&0xfffff)
+	binary.BigEndian.PutUint16(b[4:6], h.PayloadLength)
+	b[6] = h.NextHeader
+	b[7] = h.HopLimit
+	copy(b[8:24], h.Src.To16())
+	copy(b[24:40], h.Dst.To16())
+	return append(b, h.Payload...), nil
+}
+
+// ParseIPv6 decodes an IPv6 header
+func ParseIPv6(b []byte) (*IPv6, error) {
+	if len(b) < 40 {
+		return nil, ErrTruncated
+	}
+	if b[0]>>4 != 6 {
+		return nil, fmt.Errorf("packet: not an ipv6 header (version %d)", b[0]>>4)
+	}
+
+	first := binary.BigEndian.Uint32(b[0:4])
+	h := &IPv6{
+		TrafficClass:  uint8(first >> 20),
+		FlowLabel:     first & 0xfffff,
+		PayloadLength: binary.BigEndian.Uint16(b[4:6]),
+		NextHeader:    b[6],
+		HopLimit:      b[7],
+		Src:           net.IP(append([]byte(nil), b[8:24]...)),
+		Dst:           net.IP(append([]byte(nil), b[24:40]...)),
+	}
+
+	end := 40 + int(h.PayloadLength)
+	if end > len(b) {
+		end = len(b)
+	}
+	h.Payload = b[40:end]
+	return h, nil
+}
@@ -0,0 +1,72 @@
+// Package packet builds and parses the IPv4, IPv6, TCP, UDP and ICMP
+// headers used by gomap's raw socket scans, and the DNS messages used
+// for zone transfers and service discovery.
+//
+// Each header type has a Marshal method which fills in the length and
+// checksum fields and returns the header followed by its Payload, and
+// a Parse function which decodes a header and sets Payload to the bytes
+// following it.
+package packet
+
+import (
+	"encoding/binary"
+	"errors"
+	"net"
+)
+
+// IP protocol numbers
+const (
+	ProtocolICMP   = 1
+	ProtocolTCP    = 6
+	ProtocolUDP    = 17
+	ProtocolICMPv6 = 58
+)
+
+// ErrTruncated is returned when a buffer is too short to hold the header being parsed
+var ErrTruncated = errors.New("packet: truncated")
+
+// Checksum returns the internet checksum (RFC 1071) of data
+func Checksum(data []byte) uint16 {
+	return ^fold(sum(data, 0))
+}
+
+// TransportChecksum returns the checksum of a TCP, UDP or ICMPv6 segment
+// including the IPv4 or IPv6 pseudo header built from src and dst
+func TransportChecksum(proto uint8, src, dst net.IP, segment []byte) uint16 {
+	var s uint32
+	if src4, dst4 := src.To4(), dst.To4(); src4 != nil && dst4 != nil {
+		pseudo := make([]byte, 12)
+		copy(pseudo[0:4], src4)
+		copy(pseudo[4:8], dst4)
+		pseudo[9] = proto
+		binary.BigEndian.PutUint16(pseudo[10:12], uint16(len(segment)))
+		s = sum(pseudo, 0)
+	} else {
+		pseudo := make([]byte, 40)
+		copy(pseudo[0:16], src.To16())
+		copy(pseudo[16:32], dst.To16())
+		binary.BigEndian.PutUint32(pseudo[32:36], uint32(len(segment)))
+		pseudo[39] = proto
+		s = sum(pseudo, 0)
+	}
+	return ^fold(sum(segment, s))
+}
+
+// sum adds data to s as a sequence of 16 bit words, padding an odd byte with zero
+func sum(data []byte, s uint32) uint32 {
+	for i := 0; i+1 < len(data); i += 2 {
+		s += uint32(data[i])<<8 | uint32(data[i+1])
+	}
+	if len(data)%2 != 0 {
+		s += uint32(data[len(data)-1]) << 8
+	}
+	return s
+}
+
+// fold adds the carries of a 32 bit sum back into the low 16 bits
+func fold(s uint32) uint16 {
+	for s>>16 != 0 {
+		s = (s >> 16) + (s & 0xffff)
+	}
+	return uint16(s)
+}
@@ -0,0 +1,346 @@
+package packet
+
+import (
+	"bytes"
+	"encoding/hex"
+	"net"
+	"testing"
+)
+
+// captures were recorded on the loopback interface. Loopback leaves the
+// TCP and UDP checksums to offload so those were filled in afterwards.
+var captures = []struct {
+	name     string
+	data     string
+	proto    uint8
+	sport    uint16
+	dport    uint16
+	flags    TCPFlags
+	icmpType uint8
+	icmpCode uint8
+	payload  string
+}{
+	{
+		name:  "ipv4 tcp syn",
+		data:  "4500003c79a240004006c3177f0000017f000001a8ba9c409f1d982700000000a002ffd7614b00000204ffd70402080a9e35d43d000000000103030a",
+		proto: ProtocolTCP,
+		sport: 43194,
+		dport: 40000,
+		flags: TCPSyn,
+	},
+	{
+		name:  "ipv4 tcp syn-ack",
+		data:  "4500003c0000400040063cba7f0000017f0000019c40a8bafb62206b9f1d9828a012ffcbb80000000204ffd70402080af1a59bd19e35d43d0103030a",
+		proto: ProtocolTCP,
+		sport: 40000,
+		dport: 43194,
+		flags: TCPSyn | TCPAck,
+	},
+	{
+		name:    "ipv4 tcp psh-ack",
+		data:    "4500003979a440004006c3187f0000017f000001a8ba9c409f1d9828fb62206c801800409ca000000101080a9e35d43df1a59bd168656c6c6f",
+		proto:   ProtocolTCP,
+		sport:   43194,
+		dport:   40000,
+		flags:   TCPPsh | TCPAck,
+		payload: "hello",
+	},
+	{
+		name:  "ipv4 tcp rst-ack",
+		data:  "450000280000400040063cce7f0000017f0000010001cbe2000000008f4862c650140000f3db0000",
+		proto: ProtocolTCP,
+		sport: 1,
+		dport: 52194,
+		flags: TCPRst | TCPAck,
+	},
+	{
+		name:    "ipv4 udp",
+		data:    "45000021e9544000401153757f0000017f0000019e319c41000dcd9b6461746121",
+		proto:   ProtocolUDP,
+		sport:   40497,
+		dport:   40001,
+		payload: "data!",
+	},
+	{
+		name:     "ipv4 icmp port unreachable",
+		data:     "45c0003cff8a000040017c747f0000017f000001030381c50000000045000020e93b40004011538f7f0000017f0000019e310009000cfe1f70696e67",
+		proto:    ProtocolICMP,
+		icmpType: ICMPDestinationUnreachable,
+		icmpCode: 3,
+	},
+	{
+		name:     "ipv4 icmp echo reply",
+		data:     "45000021ffa5000040017d347f0000017f0000010000a8f912340001676f6d6170",
+		proto:    ProtocolICMP,
+		icmpType: ICMPEchoReply,
+		payload:  "gomap",
+	},
+	{
+		name:  "ipv6 tcp syn",
+		data:  "6003c70d002806400000000000000000000000000000000100000000000000000000000000000001a8320001308f2f7100000000a002ffc41de900000204ffc40402080a04e42325000000000103030a",
+		proto: ProtocolTCP,
+		sport: 43058,
+		dport: 1,
+		flags: TCPSyn,
+	},
+	{
+		name:    "ipv6 udp",
+		data:    "60075793000a11400000000000000000000000000000000100000000000000000000000000000001b4720009000ad5267636",
+		proto:   ProtocolUDP,
+		sport:   46194,
+		dport:   9,
+		payload: "v6",
+	},
+	{
+		name:     "ipv6 icmpv6 port unreachable",
+		data:     "600fe8e4003a3a40000000000000000000000000000000010000000000000000000000000000000101040ac60000000060075793000a11400000000000000000000000000000000100000000000000000000000000000001b4720009000a001d7636",
+		proto:    ProtocolICMPv6,
+		icmpType: ICMPv6DestinationUnreachable,
+		icmpCode: 4,
+	},
+}
+
+// network decodes the ip header of a capture
+func network(t *testing.T, data []byte) (proto uint8, src, dst net.IP, payload []byte) {
+	t.Helper()
+
+	if data[0]>>4 == 4 {
+		ip, err := ParseIPv4(data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return ip.Protocol, ip.Src, ip.Dst, ip.Payload
+	}
+
+	ip, err := ParseIPv6(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ip.NextHeader, ip.Src, ip.Dst, ip.Payload
+}
+
+func TestDecode(t *testing.T) {
+	for _, c := range captures {
+		t.Run(c.name, func(t *testing.T) {
+			data, _ := hex.DecodeString(c.data)
+			proto, _, _, segment := network(t, data)
+			if proto != c.proto {
+				t.Fatalf("protocol = %d, want %d", proto, c.proto)
+			}
+
+			var sport, dport uint16
+			var payload []byte
+			switch proto {
+			case ProtocolTCP:
+				tcp, err := ParseTCP(segment)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if tcp.Flags != c.flags {
+					t.Errorf("flags = %s, want %s", tcp.Flags, c.flags)
+				}
+				sport, dport, payload = tcp.SrcPort, tcp.DstPort, tcp.Payload
+			case ProtocolUDP:
+				udp, err := ParseUDP(segment)
+				if err != nil {
+					t.Fatal(err)
+				}
+				sport, dport, payload = udp.SrcPort, udp.DstPort, udp.Payload
+			default:
+				icmp, err := ParseICMP(segment)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if icmp.Type != c.icmpType || icmp.Code != c.icmpCode {
+					t.Errorf("type/code = %d/%d, want %d/%d", icmp.Type, icmp.Code, c.icmpType, c.icmpCode)
+				}
+				if c.payload != "" {
+					payload = icmp.Payload
+				}
+			}
+
+			if sport != c.sport || dport != c.dport {
+				t.Errorf("ports = %d -> %d, want %d -> %d", sport, dport, c.sport, c.dport)
+			}
+			if string(payload) != c.payload {
+				t.Errorf("payload = %q, want %q", payload, c.payload)
+			}
+		})
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	for _, c := range captures {
+		t.Run(c.name, func(t *testing.T) {
+			data, _ := hex.DecodeString(c.data)
+			proto, src, dst, segment := network(t, data)
+
+			var got []byte
+			var err error
+			switch proto {
+			case ProtocolTCP:
+				tcp, perr := ParseTCP(segment)
+				if perr != nil {
+					t.Fatal(perr)
+				}
+				tcp.Checksum = 0
+				got, err = tcp.Marshal(src, dst)
+			case ProtocolUDP:
+				udp, perr := ParseUDP(segment)
+				if perr != nil {
+					t.Fatal(perr)
+				}
+				udp.Checksum = 0
+				got, err = udp.Marshal(src, dst)
+			case ProtocolICMP:
+				icmp, perr := ParseICMP(segment)
+				if perr != nil {
+					t.Fatal(perr)
+				}
+				got, err = icmp.Marshal()
+			case ProtocolICMPv6:
+				icmp, perr := ParseICMP(segment)
+				if perr != nil {
+					t.Fatal(perr)
+				}
+				got, err = icmp.MarshalIPv6(src, dst)
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(got, segment) {
+				t.Fatalf("marshal = %x\nwant      %x", got, segment)
+			}
+
+			// Rebuild the ip header around the marshaled segment
+			if data[0]>>4 == 4 {
+				ip, _ := ParseIPv4(data)
+				ip.Payload = got
+				got, err = ip.Marshal()
+			} else {
+				ip, _ := ParseIPv6(data)
+				ip.Payload = got
+				got, err = ip.Marshal()
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(got, data) {
+				t.Fatalf("marshal = %x\nwant      %x", got, data)
+			}
+		})
+	}
+}
+
+func TestTCPOptions(t *testing.T) {
+	data, _ := hex.DecodeString(captures[0].data)
+	ip, err := ParseIPv4(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tcp, err := ParseTCP(ip.Payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []TCPOption{
+		MSSOption(65495),
+		SACKPermittedOption(),
+		TimestampsOption(0x9e35d43d, 0),
+		NOPOption(),
+		WindowScaleOption(10),
+	}
+	if len(tcp.Options) != len(want) {
+		t.Fatalf("got %d options, want %d", len(tcp.Options), len(want))
+	}
+	for i := range want {
+		if tcp.Options[i].Kind != want[i].Kind || !bytes.Equal(tcp.Options[i].Data, want[i].Data) {
+			t.Errorf("option %d = %+v, want %+v", i, tcp.Options[i], want[i])
+		}
+	}
+
+	// Options are padded to a multiple of four bytes
+	tcp.Options = []TCPOption{MSSOption(1460), WindowScaleOption(7)}
+	b, err := tcp.Marshal(ip.Src, ip.Dst)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tcp.DataOffset != 7 || !bytes.Equal(b[20:28], []byte{2, 4, 0x05, 0xb4, 3, 3, 7, 0}) {
+		t.Errorf("padded options = %x with data offset %d", b[20:28], tcp.DataOffset)
+	}
+}
+
+func TestChecksumLength(t *testing.T) {
+	src, dst := net.ParseIP("192.0.2.1"), net.ParseIP("192.0.2.2")
+
+	// Segments longer than 255 bytes need the full pseudo header length
+	tests := []int{0, 1, 255, 256, 1400}
+	for _, n := range tests {
+		tcp := TCP{SrcPort: 1, DstPort: 2, Flags: TCPAck, Payload: bytes.Repeat([]byte{0xa5}, n)}
+		b, err := tcp.Marshal(src, dst)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if sum := TransportChecksum(ProtocolTCP, src, dst, b); sum != 0 {
+			t.Errorf("payload %d: checksum does not verify (%#04x)", n, sum)
+		}
+	}
+}
+
+func TestDNS(t *testing.T) {
+	// A response using name compression with CNAME, A, TXT and SRV records
+	data, _ := hex.DecodeString("12348580000100030000000103777777076578616d706c6503636f6d0000010001c00c000500010000012c000603776562c010c02d000100010000012c0004c000020ac00c001000010000003c000a0568656c6c6f03666f6fc00c002100010000003c0008000a000501bbc010")
+
+	d, err := ParseDNS(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d.ID != 0x1234 || d.Flags&DNSResponse == 0 || d.Rcode() != 0 {
+		t.Errorf("header = %#04x %#04x", d.ID, d.Flags)
+	}
+	if len(d.Questions) != 1 || d.Questions[0].Name != "www.example.com." || d.Questions[0].Type != DNSTypeA {
+		t.Fatalf("questions = %+v", d.Questions)
+	}
+	if len(d.Answers) != 3 || len(d.Additionals) != 1 {
+		t.Fatalf("got %d answers and %d additionals", len(d.Answers), len(d.Additionals))
+	}
+
+	if a := d.Answers[0]; a.Type != DNSTypeCNAME || a.Name != "www.example.com." || a.Target != "web.example.com." {
+		t.Errorf("cname = %+v", a)
+	}
+	if a := d.Answers[1]; a.Name != "web.example.com." || !a.IP.Equal(net.ParseIP("192.0.2.10")) || a.TTL != 300 {
+		t.Errorf("a = %+v", a)
+	}
+	if a := d.Answers[2]; len(a.Text) != 2 || a.Text[0] != "hello" || a.Text[1] != "foo" {
+		t.Errorf("txt = %q", a.Text)
+	}
+	if a := d.Additionals[0]; a.Priority != 10 || a.Weight != 5 || a.Port != 443 || a.Target != "example.com." {
+		t.Errorf("srv = %+v", a)
+	}
+
+	// Queries round trip without compression
+	q := DNS{ID: 7, Flags: DNSRecursionDesired, Questions: []DNSQuestion{{Name: "example.com", Type: DNSTypeAXFR, Class: DNSClassINET}}}
+	b, err := q.Marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+	d, err = ParseDNS(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d.ID != 7 || len(d.Questions) != 1 || d.Questions[0] != (DNSQuestion{Name: "example.com.", Type: DNSTypeAXFR, Class: DNSClassINET}) {
+		t.Errorf("query = %+v", d)
+	}
+
+	// Compression pointers that loop are rejected
+	loop := append(append([]byte{}, data[:12]...), 0xc0, 0x0c)
+	if _, err := ParseDNS(loop); err != ErrDNSName {
+		t.Errorf("looping name error = %v", err)
+	}
+
+	// Records whose data does not decode are rejected
+	short, _ := hex.DecodeString("1234858000000001000000000000210001000000000002000a")
+	if _, err := ParseDNS(short); err != ErrTruncated {
+		t.Errorf("short srv error = %v", err)
+	}
+}
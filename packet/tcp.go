@@ -0,0 +1,232 @@
+package packet
+
+import (
+	"encoding/binary"
+	"fmt"
+	"net"
+	"strings"
+)
+
+// TCPFlags are the control bits of a TCP header
+type TCPFlags uint8
+
+// TCP header flags
+const (
+	TCPFin TCPFlags = 1 << iota
+	TCPSyn
+	TCPRst
+	TCPPsh
+	TCPAck
+	TCPUrg
+	TCPEce
+	TCPCwr
+)
+
+// String returns the set flags separated by |, for example SYN|ACK
+func (f TCPFlags) String() string {
+	names := []string{"FIN", "SYN", "RST", "PSH", "ACK", "URG", "ECE", "CWR"}
+
+	var set []string
+	for i, n := range names {
+		if f&(1<<uint(i)) != 0 {
+			set = append(set, n)
+		}
+	}
+	if len(set) == 0 {
+		return "NONE"
+	}
+	return strings.Join(set, "|")
+}
+
+// TCP option kinds
+const (
+	TCPOptionEnd           = 0
+	TCPOptionNOP           = 1
+	TCPOptionMSS           = 2
+	TCPOptionWindowScale   = 3
+	TCPOptionSACKPermitted = 4
+	TCPOptionSACK          = 5
+	TCPOptionTimestamps    = 8
+)
+
+// TCPOption is a single TCP option. The length byte is derived from Data
+// and is not written for the single byte End and NOP options.
+type TCPOption struct {
+	Kind uint8
+	Data []byte
+}
+
+// MSSOption returns a maximum segment size option
+func MSSOption(mss uint16) TCPOption {
+	data := make([]byte, 2)
+	binary.BigEndian.PutUint16(data, mss)
+	return TCPOption{Kind: TCPOptionMSS, Data: data}
+}
+
+// WindowScaleOption returns a window scale option
+func WindowScaleOption(shift uint8) TCPOption {
+	return TCPOption{Kind: TCPOptionWindowScale, Data: []byte{shift}}
+}
+
+// SACKPermittedOption returns a selective acknowledgement permitted option
+func SACKPermittedOption() TCPOption {
+	return TCPOption{Kind: TCPOptionSACKPermitted}
+}
+
+// TimestampsOption returns a timestamps option
+func TimestampsOption(value, echo uint32) TCPOption {
+	data := make([]byte, 8)
+	binary.BigEndian.PutUint32(data[0:4], value)
+	binary.BigEndian.PutUint32(data[4:8], echo)
+	return TCPOption{Kind: TCPOptionTimestamps, Data: data}
+}
+
+// NOPOption returns a no-operation option used to align other options
+func NOPOption() TCPOption {
+	return TCPOption{Kind: TCPOptionNOP}
+}
+
+// TCP is a TCP header
+type TCP struct {
+	SrcPort    uint16
+	DstPort    uint16
+	Seq        uint32
+	Ack        uint32
+	DataOffset uint8
+	Flags      TCPFlags
+	Window     uint16
+	Checksum   uint16
+	Urgent     uint16
+	Options    []TCPOption
+	Payload    []byte
+}
+
+// Option returns the first option of kind
+func (t *TCP) Option(kind uint8) (TCPOption, bool) {
+	for _, o := range t.Options {
+		if o.Kind == kind {
+			return o, true
+		}
+	}
+	return TCPOption{}, false
+}
+
+// Marshal sets the data offset and checksum fields and returns the header and
+// payload. src and dst are the addresses used for the checksum pseudo header.
+func (t *TCP) Marshal(src, dst net.IP) ([]byte, error) {
+	opts, err := encodeTCPOptions(t.Options)
+	if err != nil {
+		return nil, err
+	}
+
+	hlen := 20 + len(opts)
+	t.DataOffset = uint8(hlen / 4)
+
+	b := make([]byte, hlen, hlen+len(t.Payload))
+	binary.BigEndian.PutUint16(b[0:2], t.SrcPort)
+	binary.BigEndian.PutUint16(b[2:4], t.DstPort)
+	binary.BigEndian.PutUint32(b[4:8], t.Seq)
+	binary.BigEndian.PutUint32(b[8:12], t.Ack)
+	b[12] = t.DataOffset << 4
+	b[13] = uint8(t.Flags)
+	binary.BigEndian.PutUint16(b[14:16], t.Window)
+	binary.BigEndian.PutUint16(b[18:20], t.Urgent)
+	copy(b[20:], opts)
+	b = append(b, t.Payload...)
+
+	t.Checksum = TransportChecksum(ProtocolTCP, src, dst, b)
+	binary.BigEndian.PutUint16(b[16:18], t.Checksum)
+	return b, nil
+}
+
+// ParseTCP decodes a TCP header and its options
+func ParseTCP(b []byte) (*TCP, error) {
+	if len(b) < 20 {
+		return nil, ErrTruncated
+	}
+
+	t := &TCP{
+		SrcPort:    binary.BigEndian.Uint16(b[0:2]),
+		DstPort:    binary.BigEndian.Uint16(b[2:4]),
+		Seq:        binary.BigEndian.Uint32(b[4:8]),
+		Ack:        binary.BigEndian.Uint32(b[8:12]),
+		DataOffset: b[12] >> 4,
+		Flags:      TCPFlags(b[13]),
+		Window:     binary.BigEndian.Uint16(b[14:16]),
+		Checksum:   binary.BigEndian.Uint16(b[16:18]),
+		Urgent:     binary.BigEndian.Uint16(b[18:20]),
+	}
+
+	hlen := int(t.DataOffset) * 4
+	if hlen < 20 {
+		return nil, fmt.Errorf("packet: invalid tcp data offset %d", t.DataOffset)
+	}
+	if len(b) < hlen {
+		return nil, ErrTruncated
+	}
+
+	opts, err := decodeTCPOptions(b[20:hlen])
+	if err != nil {
+		return nil, err
+	}
+	t.Options = opts
+	t.Payload = b[hlen:]
+	return t, nil
+}
+
+// encodeTCPOptions encodes options padded with zeros to a multiple of 4 bytes
+func encodeTCPOptions(opts []TCPOption) ([]byte, error) {
+	var b []byte
+	for _, o := range opts {
+		if o.Kind == TCPOptionEnd || o.Kind == TCPOptionNOP {
+			b = append(b, o.Kind)
+			continue
+		}
+		if len(o.Data) > 253 {
+			return nil, fmt.Errorf("packet: tcp option %d too long", o.Kind)
+		}
+		b = append(b, o.Kind, uint8(len(o.Data)+2))
+		b = append(b, o.Data...)
+	}
+
+	for len(b)%4 != 0 {
+		b = append(b, TCPOptionEnd)
+	}
+	if len(b) > 40 {
+		return nil, fmt.Errorf("packet: tcp options too long (%d bytes)", len(b))
+	}
+	return b, nil
+}
+
+// decodeTCPOptions decodes options up to the end of option list
+func decodeTCPOptions(b []byte) ([]TCPOption, error) {
+	var opts []TCPOption
+	for i := 0; i < len(b); {
+		kind := b[i]
+		switch kind {
+		case TCPOptionEnd:
+			// Anything after the end of the list is padding
+			return opts, nil
+		case TCPOptionNOP:
+			opts = append(opts, TCPOption{Kind: kind})
+			i++
+			continue
+		}
+
+		if i+1 >= len(b) {
+			return nil, ErrTruncated
+		}
+		length := int(b[i+1])
+		if length < 2 || i+length > len(b) {
+			return nil, fmt.Errorf("packet: invalid length %d for tcp option %d", length, kind)
+		}
+
+		opt := TCPOption{Kind: kind}
+		if length > 2 {
+			opt.Data = b[i+2 : i+length]
+		}
+		opts = append(opts, opt)
+		i += length
+	}
+	return opts, nil
+}
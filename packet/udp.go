@@ -0,0 +1,60 @@
+package packet
+
+import (
+	"encoding/binary"
+	"fmt"
+	"net"
+)
+
+// UDP is a UDP header
+type UDP struct {
+	SrcPort  uint16
+	DstPort  uint16
+	Length   uint16
+	Checksum uint16
+	Payload  []byte
+}
+
+// Marshal sets the length and checksum fields and returns the header and
+// payload. src and dst are the addresses used for the checksum pseudo header.
+func (u *UDP) Marshal(src, dst net.IP) ([]byte, error) {
+	if 8+len(u.Payload) > 0xffff {
+		return nil, fmt.Errorf("packet: udp datagram too long (%d bytes)", 8+len(u.Payload))
+	}
+	u.Length = uint16(8 + len(u.Payload))
+
+	b := make([]byte, 8, int(u.Length))
+	binary.BigEndian.PutUint16(b[0:2], u.SrcPort)
+	binary.BigEndian.PutUint16(b[2:4], u.DstPort)
+	binary.BigEndian.PutUint16(b[4:6], u.Length)
+	b = append(b, u.Payload...)
+
+	// A zero checksum means no checksum so it is sent as all ones
+	u.Checksum = TransportChecksum(ProtocolUDP, src, dst, b)
+	if u.Checksum == 0 {
+		u.Checksum = 0xffff
+	}
+	binary.BigEndian.PutUint16(b[6:8], u.Checksum)
+	return b, nil
+}
+
+// ParseUDP decodes a UDP header
+func ParseUDP(b []byte) (*UDP, error) {
+	if len(b) < 8 {
+		return nil, ErrTruncated
+	}
+
+	u := &UDP{
+		SrcPort:  binary.BigEndian.Uint16(b[0:2]),
+		DstPort:  binary.BigEndian.Uint16(b[2:4]),
+		Length:   binary.BigEndian.Uint16(b[4:6]),
+		Checksum: binary.BigEndian.Uint16(b[6:8]),
+	}
+
+	end := int(u.Length)
+	if end < 8 || end > len(b) {
+		end = len(b)
+	}
+	u.Payload = b[8:end]
+	return u, nil
+}